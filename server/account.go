@@ -0,0 +1,21 @@
+package server
+
+import "time"
+
+// Account ties a player's SSH key to their robot, so they get it back each time they connect
+type Account struct {
+	Key       string    `json:"key" storm:"id"` // SHA256 fingerprint of the public key
+	Name      string    `json:"name"`
+	RobotID   string    `json:"robot_id"` // a secret like any robot ID, only ever shown to admins in exports
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Account returns the account for key, or nil if there isn't one yet
+func (g *Game) Account(key string) (*Account, error) {
+	return g.store.Account(key)
+}
+
+// SaveAccount adds or replaces an account
+func (g *Game) SaveAccount(a *Account) error {
+	return g.store.SaveAccount(a)
+}
@@ -1,9 +1,12 @@
 package server
 
 import (
+	"crypto/subtle"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -18,17 +21,33 @@ func New(g *Game) (http.Handler, error) {
 
 	routes := map[string]map[string]f{
 		"GET": {
-			"/state" + password: getState,
-			"/robots/{id}":      getRobot,
+			"/state" + password:          getState,
+			"/state" + password + ".png": getStatePNG,
+			"/robots/{id}":               getRobot,
+			"/events":                    getEvents,
+			"/rounds":                    getRounds,
+			"/rounds/{round}/replay":     getReplay,
+			"/rounds/{round}/replay.gif": getReplayGIF,
+			"/admin/export":              admin(getExport),
+			"/admin/population":          admin(getPopulation),
+			"/admin/spawn-points":        admin(getSpawnPoints),
 		},
 		"POST": {
 			"/robots":             postRobot,
 			"/robots/{id}/move":   postMove,
 			"/robots/{id}/turn":   postTurn,
 			"/robots/{id}/attack": postAttack,
+			"/robots/{id}/resume": postResume,
+			"/admin/import":       admin(postImport),
+			"/admin/npcs":         admin(postNPC),
+		},
+		"PUT": {
+			"/admin/population":   admin(putPopulation),
+			"/admin/spawn-points": admin(putSpawnPoints),
 		},
 		"DELETE": {
-			"/robots/{id}": deleteRobot,
+			"/robots/{id}":     deleteRobot,
+			"/admin/npcs/{id}": admin(deleteNPC),
 		},
 	}
 
@@ -61,6 +80,42 @@ func loggingMiddleware(next http.Handler) http.Handler {
 
 type f func(g *Game, w http.ResponseWriter, r *http.Request) (interface{}, error)
 
+// admin only lets requests through with "Authorization: Bearer <admin token>"
+func admin(f f) f {
+	return func(g *Game, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+		if g.adminToken == "" {
+			w.WriteHeader(http.StatusForbidden)
+			return nil, fmt.Errorf("admin endpoints are disabled")
+		}
+		token := r.Header.Get("Authorization")
+		if subtle.ConstantTimeCompare([]byte(token), []byte("Bearer "+g.adminToken)) != 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return nil, fmt.Errorf("not an admin")
+		}
+		return f(g, w, r)
+	}
+}
+
+// wantsASCII is true when a request asks for text, with ?format=ascii or by accepting text/plain
+func wantsASCII(r *http.Request) bool {
+	if r.URL.Query().Get("format") == "ascii" {
+		return true
+	}
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		if mediaType := strings.TrimSpace(strings.Split(accept, ";")[0]); mediaType == "text/plain" {
+			return true
+		}
+	}
+	return false
+}
+
+// writeASCII sends text instead of the JSON handlerWrapper would
+func writeASCII(w http.ResponseWriter, text string) (interface{}, error) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, err := w.Write([]byte(text))
+	return nil, err
+}
+
 func handlerWrapper(g *Game, f f) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Add("Content-Type", "application/json")
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+)
+
+// asciiArrows draws each direction, indexed by North, East, South and West
+const asciiArrows = "^>v<"
+
+var directionNames = []string{"north", "east", "south", "west"}
+
+// RenderASCII draws the board as text, with a line about each robot underneath
+func (g *Game) RenderASCII() (string, error) {
+	w := g.world
+	w.mu.RLock()
+	s, robots := w.state, w.copyRobots()
+	w.mu.RUnlock()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "round %d  tick %d\n\n", s.Round, s.Tick)
+	drawASCII(&b, s.Grid, robots, func(x, y int) bool { return true })
+	b.WriteString("\n")
+	for _, r := range robots {
+		fmt.Fprintln(&b, describeRobot(r))
+	}
+	return b.String(), nil
+}
+
+// RenderRobotASCII draws only what a robot can see: its own cell and the ones next to it
+func (g *Game) RenderRobotASCII(id string) (string, error) {
+	r, err := g.Robot(id)
+	if err != nil {
+		return "", err
+	}
+
+	robots := []Robot{*r}
+	for _, other := range r.InRange {
+		robots = append(robots, Robot{Name: other.Name, X: other.X, Y: other.Y, Direction: other.Direction})
+	}
+	visible := func(x, y int) bool { return distance(Location{X: x, Y: y}, Location{X: r.X, Y: r.Y}) <= 1 }
+
+	var b strings.Builder
+	fmt.Fprintln(&b, describeRobot(*r))
+	b.WriteString("\n")
+	drawASCII(&b, gridSize, robots, visible)
+	b.WriteString("\n")
+	if len(r.InRange) == 0 {
+		b.WriteString("nothing in range\n")
+	}
+	for _, other := range r.InRange {
+		fmt.Fprintf(&b, "in range: %-4s x=%-2d y=%-2d facing %s\n", other.Name, other.X, other.Y, directionNames[other.Direction])
+	}
+	return b.String(), nil
+}
+
+// drawASCII writes the grid four characters to a cell: a robot's initials then the way it faces, "." for an
+// empty cell, or blank for one that can't be seen. Dead robots face "x".
+func drawASCII(b *strings.Builder, grid int, robots []Robot, visible func(x, y int) bool) {
+	cells := map[Location]Robot{}
+	for _, r := range robots {
+		if other, taken := cells[Location{X: r.X, Y: r.Y}]; taken && !other.Dead {
+			// The living are drawn over the dead
+			continue
+		}
+		cells[Location{X: r.X, Y: r.Y}] = r
+	}
+
+	b.WriteString("   ")
+	for x := 0; x < grid; x++ {
+		fmt.Fprintf(b, "%4d", x)
+	}
+	b.WriteString("\n")
+
+	for y := 0; y < grid; y++ {
+		row := fmt.Sprintf("%3d", y)
+		for x := 0; x < grid; x++ {
+			cell := "    "
+			if r, exists := cells[Location{X: x, Y: y}]; exists {
+				arrow := asciiArrows[r.Direction : r.Direction+1]
+				if r.Dead {
+					arrow = "x"
+				}
+				cell = fmt.Sprintf("%3.2s%s", r.Name, arrow)
+			} else if visible(x, y) {
+				cell = "   ."
+			}
+			row += cell
+		}
+		b.WriteString(strings.TrimRight(row, " ") + "\n")
+	}
+}
+
+func describeRobot(r Robot) string {
+	s := fmt.Sprintf("%-4s x=%-2d y=%-2d facing %-5s score=%d", r.Name, r.X, r.Y, directionNames[r.Direction], r.Score)
+	if r.Dead {
+		s += " dead"
+	}
+	return s
+}
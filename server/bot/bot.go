@@ -0,0 +1,120 @@
+// Package bot runs robots that play themselves. Implement Brain and hand it to a Runner, which takes care of the
+// rest: joining, pacing actions, reconnecting and sitting out rounds after dying. Bots written in other languages
+// play through a Process.
+package bot
+
+import (
+	"fmt"
+
+	"github.com/fanatic/robot-game/server/wire"
+)
+
+// Action is what a robot does next
+type Action int
+
+// The actions a Brain can choose
+const (
+	Wait Action = iota
+	Move
+	TurnLeft
+	TurnRight
+	Attack
+)
+
+func (a Action) String() string {
+	switch a {
+	case Wait:
+		return "wait"
+	case Move:
+		return "move"
+	case TurnLeft:
+		return "turn-left"
+	case TurnRight:
+		return "turn-right"
+	case Attack:
+		return "attack"
+	}
+	return "unknown"
+}
+
+// ParseAction reads an action as String writes it. "left" and "right" do for turns.
+func ParseAction(s string) (Action, error) {
+	switch s {
+	case "wait":
+		return Wait, nil
+	case "move":
+		return Move, nil
+	case "turn-left", "left":
+		return TurnLeft, nil
+	case "turn-right", "right":
+		return TurnRight, nil
+	case "attack":
+		return Attack, nil
+	}
+	return Wait, fmt.Errorf("unknown action %q", s)
+}
+
+// Brain decides what a robot does next from what it can see
+type Brain interface {
+	Decide(v View) Action
+}
+
+// BrainFunc lets a plain function be a Brain
+type BrainFunc func(v View) Action
+
+// Decide calls f
+func (f BrainFunc) Decide(v View) Action {
+	return f(v)
+}
+
+// View is everything a robot knows when deciding: itself, the robots next to it, and the board's size
+type View struct {
+	Robot wire.Robot `json:"robot"`
+	Grid  int        `json:"grid"`
+	Round int        `json:"round"`
+	Tick  int        `json:"tick"`
+}
+
+// offsets moves one cell in each direction, indexed by wire.North, East, South and West
+var offsets = []wire.Location{{X: 0, Y: -1}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: -1, Y: 0}}
+
+// Next is the cell one step from l in direction
+func Next(l wire.Location, direction int) wire.Location {
+	return wire.Location{X: l.X + offsets[direction].X, Y: l.Y + offsets[direction].Y}
+}
+
+// Here is where the robot is
+func (v View) Here() wire.Location {
+	return wire.Location{X: v.Robot.X, Y: v.Robot.Y}
+}
+
+// Ahead is the cell the robot faces
+func (v View) Ahead() wire.Location {
+	return Next(v.Here(), v.Robot.Direction)
+}
+
+// OnGrid is true when l is on the board
+func (v View) OnGrid(l wire.Location) bool {
+	return l.X >= 0 && l.X < v.Grid && l.Y >= 0 && l.Y < v.Grid
+}
+
+// RobotAt returns the robot seen at l, if there is one
+func (v View) RobotAt(l wire.Location) *wire.ShortRobot {
+	for i, r := range v.Robot.InRange {
+		if r.X == l.X && r.Y == l.Y {
+			return &v.Robot.InRange[i]
+		}
+	}
+	return nil
+}
+
+// Turning is the turn that faces direction soonest, or Wait if the robot already faces it
+func (v View) Turning(direction int) Action {
+	switch (direction - v.Robot.Direction + 4) % 4 {
+	case 0:
+		return Wait
+	case 3:
+		return TurnLeft
+	}
+	return TurnRight
+}
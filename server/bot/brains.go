@@ -0,0 +1,77 @@
+package bot
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Brains are the reference bots by name, each made from a seed so that the same seed plays out the same
+var Brains = map[string]func(seed int64) Brain{
+	"random": func(seed int64) Brain { return &RandomWalker{Rand: rand.New(rand.NewSource(seed))} },
+	"wall":   func(seed int64) Brain { return WallHugger{} },
+	"hunter": func(seed int64) Brain {
+		return &Hunter{RandomWalker: RandomWalker{Rand: rand.New(rand.NewSource(seed))}}
+	},
+}
+
+// RandomWalker wanders about, attacking anything it bumps into
+type RandomWalker struct {
+	Rand *rand.Rand
+}
+
+// NewRandomWalker returns a RandomWalker seeded from the time
+func NewRandomWalker() *RandomWalker {
+	return &RandomWalker{Rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+// Decide attacks what's ahead, otherwise mostly moves and sometimes turns, never off the grid
+func (b *RandomWalker) Decide(v View) Action {
+	if v.RobotAt(v.Ahead()) != nil {
+		return Attack
+	}
+	if !v.OnGrid(v.Ahead()) || b.Rand.Intn(4) == 0 {
+		if b.Rand.Intn(2) == 0 {
+			return TurnLeft
+		}
+		return TurnRight
+	}
+	return Move
+}
+
+// WallHugger heads for the edge of the grid then follows it round clockwise, attacking anything in its way
+type WallHugger struct{}
+
+// Decide moves until the grid ends ahead, then turns right
+func (WallHugger) Decide(v View) Action {
+	if v.RobotAt(v.Ahead()) != nil {
+		return Attack
+	}
+	if !v.OnGrid(v.Ahead()) {
+		return TurnRight
+	}
+	return Move
+}
+
+// Hunter turns on anything it can see and attacks it, wandering like a RandomWalker when there's nothing about
+type Hunter struct {
+	RandomWalker
+}
+
+// NewHunter returns a Hunter seeded from the time
+func NewHunter() *Hunter {
+	return &Hunter{RandomWalker: *NewRandomWalker()}
+}
+
+// Decide attacks what's ahead, or turns towards whatever's beside or behind
+func (b *Hunter) Decide(v View) Action {
+	for direction := range offsets {
+		if v.RobotAt(Next(v.Here(), direction)) == nil {
+			continue
+		}
+		if turn := v.Turning(direction); turn != Wait {
+			return turn
+		}
+		return Attack
+	}
+	return b.RandomWalker.Decide(v)
+}
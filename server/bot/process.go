@@ -0,0 +1,166 @@
+package bot
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxRestarts is how many times in a row a Process starts its program again before giving up on it
+const maxRestarts = 3
+
+// Process is a Brain played by another program, so bots can be written in any language. Each turn the program is
+// sent its View as one line of JSON on stdin and answers with one line on stdout naming an action: move, left,
+// right, attack or wait. Anything it writes to stderr is passed through.
+//
+// A program that takes longer than the timeout to answer waits that turn, and its late answer is thrown away. One
+// that exits is started again, up to maxRestarts times in a row, and waits every turn after that. Closing it stops
+// the program, which starts afresh on the next Decide, so one Process can play match after match.
+type Process struct {
+	name    string
+	args    []string
+	timeout time.Duration
+
+	mu       sync.Mutex
+	cmd      *exec.Cmd
+	stdin    io.WriteCloser
+	lines    chan string // answers, closed when the program exits
+	late     int         // answers still to come for turns that timed out, thrown away as they arrive
+	restarts int
+	err      error
+}
+
+// NewProcess returns a Brain that runs the program name with args, giving it timeout to answer each turn. The
+// program starts on the first Decide.
+func NewProcess(timeout time.Duration, name string, args ...string) (*Process, error) {
+	if _, err := exec.LookPath(name); err != nil {
+		return nil, err
+	}
+	return &Process{name: name, args: args, timeout: timeout}, nil
+}
+
+// Decide sends v to the program and returns its answer, or Wait if it doesn't give one in time
+func (p *Process) Decide(v View) Action {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cmd == nil {
+		if p.restarts >= maxRestarts {
+			return Wait
+		}
+		if err := p.start(); err != nil {
+			p.restarts++
+			p.err = err
+			return Wait
+		}
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		p.err = err
+		return Wait
+	}
+	if _, err := p.stdin.Write(append(b, '\n')); err != nil {
+		p.err = p.stop()
+		return Wait
+	}
+
+	// Answers come in the order the views went out, so this turn's is the first after any that are late
+	timeout := time.After(p.timeout)
+	for {
+		select {
+		case line, open := <-p.lines:
+			if !open {
+				p.err = p.stop()
+				return Wait
+			}
+			if p.late > 0 {
+				p.late--
+				continue
+			}
+			action, err := ParseAction(strings.ToLower(strings.TrimSpace(line)))
+			if err != nil {
+				p.err = err
+				return Wait
+			}
+			p.restarts, p.err = 0, nil
+			return action
+		case <-timeout:
+			p.late++
+			p.err = fmt.Errorf("%s took longer than %s to answer", p.name, p.timeout)
+			return Wait
+		}
+	}
+}
+
+// Err is what last went wrong with the program, nil once it answers properly again
+func (p *Process) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+// Close stops the program
+func (p *Process) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cmd != nil {
+		p.stop()
+	}
+	p.restarts = 0
+	return nil
+}
+
+// start runs the program, reading its answers in the background
+func (p *Process) start() error {
+	cmd := exec.Command(p.name, p.args...)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	lines := make(chan string, 16)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			// Every line has to arrive for late to line answers up with turns. A program that runs ahead just waits
+			// on its pipe until it's read, and stop drains what's left.
+			lines <- scanner.Text()
+		}
+	}()
+
+	p.cmd, p.stdin, p.lines = cmd, stdin, lines
+	return nil
+}
+
+// stop ends the program if it hasn't already and returns why it stopped. The next Decide starts it again.
+func (p *Process) stop() error {
+	p.stdin.Close()
+	p.cmd.Process.Kill()
+	// The reader has to finish with stdout before Wait closes it
+	for range p.lines {
+	}
+	err := p.cmd.Wait()
+	p.cmd, p.late = nil, 0
+	p.restarts++
+	if err != nil {
+		return fmt.Errorf("%s exited: %v", p.name, err)
+	}
+	return fmt.Errorf("%s exited", p.name)
+}
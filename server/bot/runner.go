@@ -0,0 +1,204 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/fanatic/robot-game/server/client"
+)
+
+const (
+	// pause is how long to wait between looks at the board while dead, or after losing the server
+	pause        = 250 * time.Millisecond
+	maxReconnect = 10 * time.Second
+)
+
+// Runner plays a robot with a Brain until its context is done
+type Runner struct {
+	client *client.Client
+	name   string
+	brain  Brain
+	log    *log.Logger
+	id     string        // robot to resume instead of joining
+	wait   time.Duration // how long Wait waits, the server's delay if zero
+	round  int           // last round seen
+	ready  time.Time     // when the last action's cooldown is over
+}
+
+// Option configures a Runner
+type Option func(r *Runner)
+
+// WithLogger logs each decision to l instead of the standard logger
+func WithLogger(l *log.Logger) Option {
+	return func(r *Runner) {
+		r.log = l
+	}
+}
+
+// WithResume plays the robot with id, if it's still around, instead of joining a new one
+func WithResume(id string) Option {
+	return func(r *Runner) {
+		r.id = id
+	}
+}
+
+// WithWait sets how long a Wait lasts
+func WithWait(d time.Duration) Option {
+	return func(r *Runner) {
+		r.wait = d
+	}
+}
+
+// NewRunner returns a Runner that plays a robot called name on c's server
+func NewRunner(c *client.Client, name string, brain Brain, opts ...Option) *Runner {
+	r := &Runner{client: c, name: name, brain: brain, log: log.Default()}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Discard is a logger for runners that shouldn't say anything
+var Discard = log.New(io.Discard, "", 0)
+
+// ID is the robot being played, once it has joined
+func (r *Runner) ID() string {
+	return r.id
+}
+
+// Run joins the game and plays until ctx is done, then takes the robot off the grid. Losing the server isn't fatal,
+// it keeps trying to reconnect.
+func (r *Runner) Run(ctx context.Context) error {
+	backoff := pause
+	for ctx.Err() == nil {
+		err := r.turn(ctx)
+		if err == nil {
+			backoff = pause
+			continue
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		var apiErr *client.Error
+		if errors.As(err, &apiErr) && apiErr.Status == 200 {
+			// Turned down by the game, not the server
+			return err
+		}
+		r.log.Printf("bot at=reconnect name=%s wait=%s err=%q\n", r.name, backoff, err)
+		sleep(ctx, backoff)
+		if backoff *= 2; backoff > maxReconnect {
+			backoff = maxReconnect
+		}
+	}
+
+	if r.id != "" {
+		// ctx is done, leave with a fresh one
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := r.client.Leave(ctx, r.id); err != nil && !errors.Is(err, client.ErrNotFound) {
+			return err
+		}
+		r.log.Printf("bot at=leave name=%s\n", r.name)
+	}
+	return nil
+}
+
+// turn looks at the board, decides and acts once
+func (r *Runner) turn(ctx context.Context) error {
+	if err := r.join(ctx); err != nil {
+		return err
+	}
+
+	state, err := r.client.State(ctx)
+	if err != nil {
+		return err
+	}
+	if state.Round != r.round {
+		r.log.Printf("bot at=round name=%s round=%d\n", r.name, state.Round)
+		r.round = state.Round
+	}
+	robot, err := r.client.Robot(ctx, r.id)
+	switch {
+	case errors.Is(err, client.ErrDead):
+		// Sit the round out, everyone respawns when it ends
+		sleep(ctx, pause)
+		return nil
+	case errors.Is(err, client.ErrNotFound):
+		r.log.Printf("bot at=gone name=%s\n", r.name)
+		r.id = ""
+		return nil
+	case err != nil:
+		return err
+	}
+
+	v := View{Robot: *robot, Grid: state.Grid, Round: state.Round, Tick: state.Tick}
+	action := r.brain.Decide(v)
+
+	// Keep to the server's pace rather than hammering it with actions it would only make wait
+	sleep(ctx, time.Until(r.ready))
+	if ctx.Err() != nil {
+		return nil
+	}
+	r.ready = time.Now().Add(state.CurrentDelay)
+
+	result := "ok"
+	switch action {
+	case Move:
+		_, err = r.client.Move(ctx, r.id)
+	case TurnLeft:
+		_, err = r.client.Turn(ctx, r.id, true)
+	case TurnRight:
+		_, err = r.client.Turn(ctx, r.id, false)
+	case Attack:
+		_, err = r.client.Attack(ctx, r.id)
+	case Wait:
+		wait := r.wait
+		if wait == 0 {
+			wait = state.CurrentDelay
+		}
+		sleep(ctx, wait)
+	default:
+		result = fmt.Sprintf("unknown action %d", action)
+	}
+
+	var apiErr *client.Error
+	if errors.As(err, &apiErr) && apiErr.Status == 200 {
+		// Walking into walls and missing are part of the game
+		result, err = apiErr.Msg, nil
+	}
+	if err != nil {
+		return err
+	}
+
+	r.log.Printf("bot at=decide name=%s round=%d tick=%d x=%d y=%d direction=%d action=%s result=%q\n",
+		r.name, v.Round, v.Tick, v.Robot.X, v.Robot.Y, v.Robot.Direction, action, result)
+	return nil
+}
+
+// join gets the robot onto the grid if it isn't already
+func (r *Runner) join(ctx context.Context) error {
+	if r.id != "" {
+		return nil
+	}
+
+	robot, err := r.client.Join(ctx, r.name)
+	if err != nil {
+		return err
+	}
+	r.id = robot.ID
+	r.log.Printf("bot at=join name=%s x=%d y=%d\n", r.name, robot.X, robot.Y)
+	return nil
+}
+
+// sleep for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
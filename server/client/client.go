@@ -0,0 +1,278 @@
+// Package client talks to a robot game server over its HTTP API.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fanatic/robot-game/server/wire"
+)
+
+const (
+	firstBackoff = 250 * time.Millisecond
+	maxBackoff   = 4 * time.Second
+)
+
+// Client makes requests to one server
+type Client struct {
+	endpoint     string
+	password     string
+	token        string
+	http         *http.Client
+	retries      int
+	pollInterval time.Duration
+}
+
+// Option configures a Client
+type Option func(c *Client)
+
+// WithPassword is the password the server keeps /state behind
+func WithPassword(password string) Option {
+	return func(c *Client) {
+		c.password = password
+	}
+}
+
+// WithToken sends token as a bearer token with every request, for admin endpoints or a proxy in front of the server
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithHTTPClient makes requests with h instead of a client with a 10 second timeout
+func WithHTTPClient(h *http.Client) Option {
+	return func(c *Client) {
+		c.http = h
+	}
+}
+
+// WithRetries sets how many times a request the server didn't get is retried, 5 by default
+func WithRetries(n int) Option {
+	return func(c *Client) {
+		c.retries = n
+	}
+}
+
+// WithPollInterval sets how often Subscribe asks for new events, a second by default
+func WithPollInterval(d time.Duration) Option {
+	return func(c *Client) {
+		c.pollInterval = d
+	}
+}
+
+// New returns a Client for the server at endpoint, like http://localhost:8000
+func New(endpoint string, opts ...Option) (*Client, error) {
+	if u, err := url.Parse(endpoint); err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("endpoint %q isn't a URL", endpoint)
+	}
+
+	c := &Client{
+		endpoint:     strings.TrimSuffix(endpoint, "/"),
+		http:         &http.Client{Timeout: 10 * time.Second},
+		retries:      5,
+		pollInterval: time.Second,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Join adds a new robot named name to the game. Keep its ID, it's the only way to control it.
+func (c *Client) Join(ctx context.Context, name string) (*wire.Robot, error) {
+	payload, err := json.Marshal(map[string]string{"name": name})
+	if err != nil {
+		return nil, err
+	}
+	return c.robot(ctx, http.MethodPost, "/robots", payload)
+}
+
+// Resume gets back a robot the client already has, dead or alive, without joining again
+func (c *Client) Resume(ctx context.Context, id string) (*wire.Robot, error) {
+	return c.robot(ctx, http.MethodPost, "/robots/"+id+"/resume", nil)
+}
+
+// Robot gets a living robot along with the robots in its range
+func (c *Client) Robot(ctx context.Context, id string) (*wire.Robot, error) {
+	return c.robot(ctx, http.MethodGet, "/robots/"+id, nil)
+}
+
+// Move a robot forward one cell
+func (c *Client) Move(ctx context.Context, id string) (*wire.Robot, error) {
+	return c.robot(ctx, http.MethodPost, "/robots/"+id+"/move", nil)
+}
+
+// Turn a robot left (true) or right (false)
+func (c *Client) Turn(ctx context.Context, id string, left bool) (*wire.Robot, error) {
+	return c.robot(ctx, http.MethodPost, "/robots/"+id+"/turn", []byte(`{"direction":`+strconv.FormatBool(left)+`}`))
+}
+
+// Attack whatever robot is in front
+func (c *Client) Attack(ctx context.Context, id string) (*wire.Robot, error) {
+	return c.robot(ctx, http.MethodPost, "/robots/"+id+"/attack", nil)
+}
+
+// Leave takes a robot off the grid for good
+func (c *Client) Leave(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/robots/"+id, nil, nil)
+}
+
+// State gets the whole board, without the robots' IDs
+func (c *Client) State(ctx context.Context) (*wire.State, error) {
+	var s wire.State
+	if err := c.do(ctx, http.MethodGet, "/state"+c.password, nil, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Events gets up to limit events after since that match filter, and the since to pass for the next page
+func (c *Client) Events(ctx context.Context, filter wire.EventFilter, since, limit int) ([]wire.Event, int, error) {
+	query := url.Values{}
+	query.Set("since", strconv.Itoa(since))
+	query.Set("limit", strconv.Itoa(limit))
+	if filter.Action != "" {
+		query.Set("type", filter.Action)
+	}
+	if filter.Robot != "" {
+		query.Set("robot", filter.Robot)
+	}
+
+	var page struct {
+		Events []wire.Event `json:"events"`
+		Next   int          `json:"next"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/events?"+query.Encode(), nil, &page); err != nil {
+		return nil, since, err
+	}
+	return page.Events, page.Next, nil
+}
+
+// Subscribe calls fn with every event after since that matches filter, as they happen, until ctx is done or a
+// request fails
+func (c *Client) Subscribe(ctx context.Context, filter wire.EventFilter, since int, fn func(wire.Event)) error {
+	const limit = 1000
+	for {
+		events, next, err := c.Events(ctx, filter, since, limit)
+		if err != nil {
+			return err
+		}
+		for _, ev := range events {
+			fn(ev)
+		}
+		since = next
+
+		if len(events) == limit {
+			// More are waiting
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(c.pollInterval):
+		}
+	}
+}
+
+func (c *Client) robot(ctx context.Context, method, path string, payload []byte) (*wire.Robot, error) {
+	var r wire.Robot
+	if err := c.do(ctx, method, path, payload, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// do makes a request, decoding the response into v unless it's nil. Requests that fail before the server could act
+// on them are retried with backoff.
+func (c *Client) do(ctx context.Context, method, path string, payload []byte, v interface{}) error {
+	backoff := firstBackoff
+	for attempt := 0; ; attempt++ {
+		err := c.try(ctx, method, path, payload, v)
+		if err == nil || attempt >= c.retries || !retryable(method, err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+func (c *Client) try(ctx context.Context, method, path string, payload []byte, v interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.endpoint+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var failure struct {
+		At  string `json:"at"`
+		Msg string `json:"msg"`
+	}
+	if json.Unmarshal(raw, &failure) == nil && failure.At == "error" {
+		return newError(resp.StatusCode, failure.Msg)
+	}
+	if resp.StatusCode >= 400 {
+		return newError(resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+
+	if v == nil {
+		return nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("unexpected response from %s %s: %v", method, path, err)
+	}
+	return nil
+}
+
+// retryable is true when trying again can't repeat an action. Moves and attacks are only retried if the server
+// was never reached.
+func retryable(method string, err error) bool {
+	idempotent := method == http.MethodGet || method == http.MethodDelete
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return idempotent && apiErr.Status >= 500
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var netErr net.Error
+	return idempotent && errors.As(err, &netErr)
+}
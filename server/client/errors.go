@@ -0,0 +1,62 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/fanatic/robot-game/server/wire"
+)
+
+// Error is the server turning a request down. The game reports most failures, like walking off the grid, alongside
+// a 200, so Status only says something for the rest.
+type Error struct {
+	Status int
+	Msg    string
+
+	err error // the wire error Msg is, if it's one the client knows
+}
+
+func newError(status int, msg string) *Error {
+	e := &Error{Status: status, Msg: msg}
+	for _, known := range refusals {
+		if msg == known.Error() {
+			e.err = known
+		}
+	}
+	return e
+}
+
+func (e *Error) Error() string {
+	if e.Status == http.StatusOK {
+		return e.Msg
+	}
+	return fmt.Sprintf("%d %s: %s", e.Status, http.StatusText(e.Status), e.Msg)
+}
+
+// Unwrap gives the wire error the server answered with, so errors.Is(err, ErrOffGrid) and the like work
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Is matches the errors only told apart by their status, like ErrUnauthorized
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Status == e.Status
+}
+
+// The errors the server answers with
+var (
+	ErrNotFound     = wire.ErrNotFound
+	ErrDead         = wire.ErrDead
+	ErrLimit        = wire.ErrLimit
+	ErrGridFull     = wire.ErrGridFull
+	ErrOffGrid      = wire.ErrOffGrid
+	ErrBlocked      = wire.ErrBlocked
+	ErrMissed       = wire.ErrMissed
+	ErrProtected    = wire.ErrProtected
+	ErrUnauthorized = &Error{Status: http.StatusUnauthorized}
+	ErrForbidden    = &Error{Status: http.StatusForbidden}
+)
+
+// refusals are the wire errors an answer is matched against
+var refusals = []error{ErrNotFound, ErrDead, ErrLimit, ErrGridFull, ErrOffGrid, ErrBlocked, ErrMissed, ErrProtected}
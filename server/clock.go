@@ -0,0 +1,104 @@
+package server
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the game what time it is and waits out action delays
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// FakeClock only moves when told to. Sleeping advances it rather than blocking, so tests and simulations don't
+// wait out action delays in real time.
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []waiter
+	changed *sync.Cond // signalled as waiters come and go
+}
+
+// waiter is an After waiting for the clock to reach at
+type waiter struct {
+	at time.Time
+	c  chan time.Time
+}
+
+// NewFakeClock returns a FakeClock stopped at now
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{now: now}
+	c.changed = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now returns the fake time
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Sleep advances the clock by d and returns immediately
+func (c *FakeClock) Sleep(d time.Duration) {
+	c.Advance(d)
+}
+
+// After returns a channel that receives the time once the clock has been moved on by d
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w := waiter{at: c.now.Add(d), c: make(chan time.Time, 1)}
+	c.waiters = append(c.waiters, w)
+	c.fire()
+	return w.c
+}
+
+// Advance moves the clock forward by d
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	c.fire()
+}
+
+// Set jumps the clock to t
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+	c.fire()
+}
+
+// BlockUntil waits until n goroutines are waiting on After, e.g. for a loop to finish what it was doing and go
+// back to sleep
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.waiters) < n {
+		c.changed.Wait()
+	}
+}
+
+// fire wakes the waiters whose time has come. The caller must hold the lock.
+func (c *FakeClock) fire() {
+	waiting := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			waiting = append(waiting, w)
+			continue
+		}
+		w.c <- c.now
+	}
+	c.waiters = waiting
+	c.changed.Broadcast()
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/fanatic/robot-game/server/bot"
+	"github.com/fanatic/robot-game/server/client"
+)
+
+func main() {
+	endpoint := flag.String("endpoint", "http://localhost:8000", "robot game API")
+	brain := flag.String("brain", "hunter", "random, wall or hunter")
+	program := flag.String("exec", "", "command line of a program to play instead of a brain, see bot.Process")
+	timeout := flag.Duration("timeout", time.Second, "how long the program has to answer each turn")
+	resume := flag.String("resume", "", "ID of a robot to play instead of joining")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: bot [FLAGS] INITIALS")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	newBrain, exists := bot.Brains[*brain]
+	if flag.NArg() != 1 || !exists {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if command := strings.Fields(*program); len(command) > 0 {
+		p, err := bot.NewProcess(*timeout, command[0], command[1:]...)
+		if err != nil {
+			log.Fatal(err)
+		}
+		newBrain = func(int64) bot.Brain { return p }
+	}
+
+	c, err := client.New(*endpoint)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	b := newBrain(time.Now().UnixNano())
+	err = bot.NewRunner(c, flag.Arg(0), b, bot.WithResume(*resume)).Run(ctx)
+	if closer, ok := b.(io.Closer); ok {
+		closer.Close()
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
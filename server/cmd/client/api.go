@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/fanatic/robot-game/server/client"
+)
+
+var (
+	endpoint = flag.String("endpoint", envOr("ROBOT_GAME_ENDPOINT", "http://localhost:8000"), "robot game API, without a trailing slash ($ROBOT_GAME_ENDPOINT)")
+	password = flag.String("password", os.Getenv("ROBOT_GAME_PASSWORD"), "password the server keeps /state behind ($ROBOT_GAME_PASSWORD)")
+	token    = flag.String("token", os.Getenv("ROBOT_GAME_TOKEN"), "sent as a bearer token with every request ($ROBOT_GAME_TOKEN)")
+	retries  = flag.Int("retries", 5, "times to retry a request the server didn't get")
+)
+
+// api is the server the flags point at
+var api *client.Client
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// connect sets up api from the flags
+func connect() error {
+	var err error
+	api, err = client.New(*endpoint, client.WithPassword(*password), client.WithToken(*token), client.WithRetries(*retries))
+	return err
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fanatic/robot-game/server"
+)
+
+var (
+	credentialsPath = flag.String("credentials", envOr("ROBOT_GAME_CREDENTIALS", defaultCredentialsPath()), "where the robot's ID is kept for --resume ($ROBOT_GAME_CREDENTIALS)")
+	resume          = flag.Bool("resume", false, "reattach to the robot in the credentials file instead of joining")
+)
+
+// Credentials are what it takes to get a robot back after the client goes away
+type Credentials struct {
+	Endpoint string `json:"endpoint"`
+	ID       string `json:"id"` // also the secret
+	Name     string `json:"name"`
+}
+
+func defaultCredentialsPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".robot-game.json"
+	}
+	return filepath.Join(home, ".robot-game.json")
+}
+
+// saveCredentials writes r's ID where only the current user can read it
+func saveCredentials(r *server.Robot) error {
+	buf, err := json.MarshalIndent(Credentials{Endpoint: *endpoint, ID: r.ID, Name: r.Name}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(*credentialsPath, buf, 0600)
+}
+
+func loadCredentials() (*Credentials, error) {
+	buf, err := os.ReadFile(*credentialsPath)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no robot to resume in %s", *credentialsPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var c Credentials
+	if err := json.Unmarshal(buf, &c); err != nil {
+		return nil, fmt.Errorf("%s: %v", *credentialsPath, err)
+	}
+	if c.Endpoint != *endpoint {
+		return nil, fmt.Errorf("the robot in %s plays on %s, not %s", *credentialsPath, c.Endpoint, *endpoint)
+	}
+	return &c, nil
+}
+
+// forgetCredentials removes the credentials file once its robot has left
+func forgetCredentials() error {
+	if err := os.Remove(*credentialsPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -1,126 +1,136 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"flag"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
 	"os"
 	"strings"
-	"time"
 
+	"github.com/fanatic/robot-game/server"
 	"github.com/nsf/termbox-go"
 )
 
-// APIEndpoint is HTTP endpoint of the robot game api (omit trailing slash)
-const APIEndpoint = "http://localhost:8000"
+const usage = `Usage:
+  client [FLAGS] INITIALS      join the game as a new robot
+  client [FLAGS] --resume      play the robot a crashed client left behind
+  client [FLAGS] spectate      watch the whole board
+  client [FLAGS] replay FILE   play back a replay file
+
+Flags:`
 
 func main() {
-	if len(os.Args) != 2 {
-		log.Fatalln("Usage: client INITIALS")
-		return
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
 	}
-	r := call("POST", "/robots", `{"name": "`+os.Args[1]+`"}`)
+	flag.Parse()
+	args := flag.Args()
 
-	if err := termbox.Init(); err != nil {
-		panic(err)
+	*endpoint = strings.TrimSuffix(*endpoint, "/")
+	if err := connect(); err != nil {
+		log.Fatal(err)
 	}
-	termbox.SetInputMode(termbox.InputEsc)
-
-	fmt.Printf("new robot: %+v\n", r)
-
-	loop(r.ID)
 
-	termbox.Close()
-
-	call("DELETE", "/robots/"+r.ID, "")
+	var err error
+	switch {
+	case *resume && len(args) == 0:
+		err = rejoin()
+	case len(args) == 2 && args[0] == "replay":
+		err = replay(args[1])
+	case len(args) == 1 && args[0] == "spectate":
+		err = spectate()
+	case len(args) == 1:
+		err = join(args[0])
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
 }
 
-func loop(id string) {
-	for {
-		switch ev := termbox.PollEvent(); ev.Type {
-		case termbox.EventKey:
-			switch ev.Key {
-			case termbox.KeyArrowUp:
-				fmt.Printf("move:       ")
-				r := call("POST", "/robots/"+id+"/move", "")
-				fmt.Println(r)
-
-			case termbox.KeyArrowLeft:
-				fmt.Printf("turn-left:  ")
-				r := call("POST", "/robots/"+id+"/turn", `{"direction":true}`)
-				fmt.Println(r)
-
-			case termbox.KeyArrowRight:
-				fmt.Printf("turn-right: ")
-				r := call("POST", "/robots/"+id+"/turn", `{"direction":false}`)
-				fmt.Println(r)
-
-			case termbox.KeySpace:
-				fmt.Printf("attack:     ")
-				r := call("POST", "/robots/"+id+"/attack", "")
-				fmt.Println(r)
-
-			case termbox.KeyEsc:
-				return
-			}
-		case termbox.EventError:
-			panic(ev.Err)
-		}
+// join plays as a new robot until esc is pressed, then takes it off the grid
+func join(name string) error {
+	r, err := api.Join(context.Background(), name)
+	if err != nil {
+		return fmt.Errorf("couldn't join: %v", err)
+	}
+	if err := saveCredentials(r); err != nil {
+		log.Printf("couldn't save credentials, the robot can't be resumed: %v\n", err)
 	}
+
+	return run(r)
 }
 
-// call makes a request to the robot game API
-func call(method, path, payload string) *Robot {
-	var body io.Reader
-	if payload != "" {
-		body = strings.NewReader(payload)
-	}
-	req, err := http.NewRequest(method, APIEndpoint+path, body)
+// rejoin plays as the robot in the credentials file
+func rejoin() error {
+	c, err := loadCredentials()
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	r, err := api.Resume(context.Background(), c.ID)
 	if err != nil {
-		log.Fatal(err)
+		return fmt.Errorf("couldn't resume %s: %v", c.Name, err)
 	}
 
-	var r Robot
-	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
-		log.Fatalln(err)
-	}
-	return &r
+	return run(r)
 }
 
-type Robot struct {
-	ID        string    `json:"id"` // also the secret
-	CreatedAt time.Time `json:"created_at"`
-	Name      string    `json:"name"`
-	X         int       `json:"x"`
-	Y         int       `json:"y"`
-	Color     string    `json:"color"`
-	Direction int       `json:"direction"`
-	Vision    int       `json:"vision"`
-	Score     int       `json:"score"`
-	Dead      bool      `json:"dead"`
-	InRange   []struct {
-		Name      string `json:"name"`
-		X         int    `json:"x"`
-		Y         int    `json:"y"`
-		Direction int    `json:"direction"`
-	} `json:"robots_in_range"`
-
-	// On error
-	At  string `json:"at"`
-	Msg string `json:"msg"`
+// run plays r until esc is pressed, then takes it off the grid
+func run(r *server.Robot) error {
+	err := func() error {
+		if err := termbox.Init(); err != nil {
+			return err
+		}
+		defer termbox.Close()
+		termbox.SetInputMode(termbox.InputEsc)
+		termbox.SetOutputMode(termbox.Output256)
+
+		return play(r)
+	}()
+
+	if leaveErr := api.Leave(context.Background(), r.ID); leaveErr != nil {
+		if err == nil {
+			err = fmt.Errorf("couldn't leave, resume with --resume: %v", leaveErr)
+		}
+		return err
+	}
+	if forgetErr := forgetCredentials(); err == nil {
+		err = forgetErr
+	}
+	return err
 }
 
-func (r *Robot) String() string {
-	if r.At == "error" {
-		return fmt.Sprintf("error=%s", r.Msg)
+// pollEvents forwards termbox's events until stop is called, which has to happen before termbox.Close
+func pollEvents() (events <-chan termbox.Event, stop func()) {
+	ch := make(chan termbox.Event)
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		for {
+			ev := termbox.PollEvent()
+			if ev.Type == termbox.EventInterrupt {
+				return
+			}
+			select {
+			case ch <- ev:
+			case <-done:
+				// stop's interrupt is on its way, it would block forever if nobody took it
+				for termbox.PollEvent().Type != termbox.EventInterrupt {
+				}
+				return
+			}
+		}
+	}()
+
+	return ch, func() {
+		close(done)
+		termbox.Interrupt()
+		<-exited
 	}
-	return fmt.Sprintf("name=%s x=%d y=%d direction=%d score=%d dead=%t in-range=%v", r.Name, r.X, r.Y, r.Direction, r.Score, r.Dead, r.InRange)
 }
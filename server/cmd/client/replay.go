@@ -0,0 +1,223 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/fanatic/robot-game/server"
+	"github.com/nsf/termbox-go"
+)
+
+// arrows draws each direction, indexed by server.North, East, South and West
+var arrows = []rune{'▲', '▶', '▼', '◀'}
+
+// player steps through the frames of a replay
+type player struct {
+	replay  *server.Replay
+	frames  []server.ReplayFrame
+	frame   int
+	playing bool
+	speed   time.Duration // between frames
+}
+
+// replay plays back the replay file at path until esc is pressed
+func replay(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r server.Replay
+	if err := json.NewDecoder(f).Decode(&r); err != nil {
+		return err
+	}
+	frames, err := r.Frames()
+	if err != nil {
+		return err
+	}
+
+	if err := termbox.Init(); err != nil {
+		return err
+	}
+	defer termbox.Close()
+	termbox.SetInputMode(termbox.InputEsc)
+	termbox.SetOutputMode(termbox.Output256)
+
+	events, stop := pollEvents()
+	defer stop()
+
+	p := &player{replay: &r, frames: frames, speed: 250 * time.Millisecond}
+	ticker := time.NewTicker(p.speed)
+	defer ticker.Stop()
+
+	for {
+		p.draw()
+
+		select {
+		case <-ticker.C:
+			if p.playing {
+				p.seek(1)
+			}
+		case ev := <-events:
+			switch ev.Type {
+			case termbox.EventKey:
+				switch {
+				case ev.Key == termbox.KeyEsc || ev.Ch == 'q':
+					return nil
+				case ev.Key == termbox.KeySpace:
+					p.playing = !p.playing
+					if p.frame == len(p.frames)-1 {
+						p.frame = 0
+					}
+				case ev.Key == termbox.KeyArrowRight || ev.Ch == '.':
+					p.playing = false
+					p.seek(1)
+				case ev.Key == termbox.KeyArrowLeft || ev.Ch == ',':
+					p.playing = false
+					p.seek(-1)
+				case ev.Key == termbox.KeyPgdn || ev.Ch == ']':
+					p.seek(10)
+				case ev.Key == termbox.KeyPgup || ev.Ch == '[':
+					p.seek(-10)
+				case ev.Key == termbox.KeyHome:
+					p.frame = 0
+				case ev.Key == termbox.KeyEnd:
+					p.frame = len(p.frames) - 1
+				case ev.Ch == '+' && p.speed > 25*time.Millisecond:
+					p.speed /= 2
+					ticker.Reset(p.speed)
+				case ev.Ch == '-' && p.speed < 4*time.Second:
+					p.speed *= 2
+					ticker.Reset(p.speed)
+				}
+			case termbox.EventError:
+				return ev.Err
+			}
+		}
+	}
+}
+
+// seek moves by n frames, stopping at either end
+func (p *player) seek(n int) {
+	p.frame += n
+	if p.frame < 0 {
+		p.frame = 0
+	}
+	if p.frame >= len(p.frames) {
+		p.frame = len(p.frames) - 1
+		p.playing = false
+	}
+}
+
+// draw renders the grid as it stands at the current frame, with the scores and kills so far alongside
+func (p *player) draw() {
+	termbox.Clear(termbox.ColorDefault, termbox.ColorDefault)
+	frame := p.frames[p.frame]
+
+	// Two columns per cell: the robot's initial then which way it faces
+	for y := 0; y < p.replay.Grid; y++ {
+		for x := 0; x < p.replay.Grid; x++ {
+			termbox.SetCell(x*2+1, y+1, '·', termbox.ColorDefault, termbox.ColorDefault)
+		}
+	}
+	for _, r := range frame.Robots {
+		fg, ch := colorOf(r.Color)|termbox.AttrBold, arrows[r.Direction]
+		if r.Dead {
+			fg, ch = termbox.ColorDefault, '✗'
+		}
+		termbox.SetCell(r.X*2, r.Y+1, []rune(r.Name + " ")[0], fg, termbox.ColorDefault)
+		termbox.SetCell(r.X*2+1, r.Y+1, ch, fg, termbox.ColorDefault)
+	}
+
+	// Panel to the right of the grid
+	x, y := p.replay.Grid*2+3, 0
+	line := func(fg termbox.Attribute, format string, args ...interface{}) {
+		printAt(x, y, fg, fmt.Sprintf(format, args...))
+		y++
+	}
+
+	state := "paused"
+	if p.playing {
+		state = "playing"
+	}
+	line(termbox.AttrBold, "round %d  frame %d/%d  %s  %s/frame", p.replay.Round, p.frame, len(p.frames)-1, state, p.speed)
+
+	if a := frame.Action; a != nil {
+		line(termbox.ColorDefault, "tick %d  %s", a.Tick, a.Time.Format("15:04:05.000"))
+		line(termbox.ColorDefault, "%s", describe(a))
+	} else {
+		line(termbox.ColorDefault, "tick %d  %s", p.replay.Tick, p.replay.Start.Format("15:04:05.000"))
+		line(termbox.ColorDefault, "round starts")
+	}
+	if frame.Winner != "" {
+		line(termbox.AttrBold, "%s wins the round", frame.Winner)
+	}
+	y++
+
+	line(termbox.AttrUnderline, "scores")
+	for _, r := range frame.Robots {
+		status := ""
+		if r.Dead {
+			status = " dead"
+		}
+		line(colorOf(r.Color), "%-4s %5d%s", r.Name, r.Score, status)
+	}
+	y++
+
+	line(termbox.AttrUnderline, "kills")
+	for i := 1; i <= p.frame; i++ {
+		if f := p.frames[i]; f.Killed != "" {
+			line(termbox.ColorDefault, "tick %-6d %s killed %s", f.Action.Tick, f.Action.Robot, f.Killed)
+		}
+	}
+
+	printAt(0, p.replay.Grid+2, termbox.ColorDefault, "space play/pause  ←/→ step  [/] or pgup/pgdn ±10  home/end  +/- speed  esc quit")
+	termbox.Flush()
+}
+
+// describe says what an action was and how it went
+func describe(a *server.ReplayAction) string {
+	s := a.Robot + " " + a.Action
+	switch a.Action {
+	case server.ActionTurn:
+		if a.Left {
+			s += " left"
+		} else {
+			s += " right"
+		}
+	case server.ActionSeed:
+		s = "reseeded with " + strconv.FormatInt(a.Seed, 10)
+	case server.ActionMap:
+		s = "spawn points changed"
+	}
+	if a.Result != "ok" {
+		s += ": " + a.Result
+	}
+	return s
+}
+
+// printAt writes s on one line starting at x, y
+func printAt(x, y int, fg termbox.Attribute, s string) {
+	for _, ch := range s {
+		termbox.SetCell(x, y, ch, fg, termbox.ColorDefault)
+		x++
+	}
+}
+
+// colorOf picks the closest of the 256 terminal colors to a #rrggbb robot color
+func colorOf(hex string) termbox.Attribute {
+	if len(hex) != 7 {
+		return termbox.ColorDefault
+	}
+	rgb, err := strconv.ParseUint(hex[1:], 16, 32)
+	if err != nil {
+		return termbox.ColorDefault
+	}
+	cube := func(v uint64) termbox.Attribute { return termbox.Attribute((v*5 + 127) / 255) }
+	// Output256 colors are offset by one, 16 is the start of the 6x6x6 color cube
+	return 17 + 36*cube(rgb>>16&0xff) + 6*cube(rgb>>8&0xff) + cube(rgb&0xff)
+}
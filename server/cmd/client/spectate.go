@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/fanatic/robot-game/server"
+	"github.com/nsf/termbox-go"
+)
+
+// killFeedLines is how many of the latest kills the feed keeps
+const killFeedLines = 15
+
+// spectator follows the whole board without a robot of its own
+type spectator struct {
+	board server.State // as the web UI sees it, robots and all
+	kills []server.Event
+	since int   // last event already in the feed
+	err   error // why the last refresh failed
+}
+
+// spectate shows the board, leaderboard and kill feed until esc is pressed
+func spectate() error {
+	if err := termbox.Init(); err != nil {
+		return err
+	}
+	defer termbox.Close()
+	termbox.SetInputMode(termbox.InputEsc)
+	termbox.SetOutputMode(termbox.Output256)
+
+	s := &spectator{board: server.State{Grid: 16}}
+
+	events, stop := pollEvents()
+	defer stop()
+
+	refreshed := make(chan *spectator)
+	refreshing := false
+	ticker := time.NewTicker(refreshEvery)
+	defer ticker.Stop()
+
+	for {
+		s.draw()
+
+		select {
+		case ev := <-events:
+			switch ev.Type {
+			case termbox.EventKey:
+				if ev.Key == termbox.KeyEsc || ev.Ch == 'q' {
+					return nil
+				}
+			case termbox.EventError:
+				return ev.Err
+			}
+
+		case <-ticker.C:
+			if refreshing {
+				continue
+			}
+			refreshing = true
+			go func(since int) {
+				next := &spectator{since: since}
+				board, err := api.State(context.Background())
+				if next.err = err; err == nil {
+					next.board = *board
+					next.err = next.fetchKills()
+				}
+				refreshed <- next
+			}(s.since)
+
+		case next := <-refreshed:
+			refreshing = false
+			if s.err = next.err; next.err != nil {
+				continue
+			}
+			s.board = next.board
+			s.since = next.since
+			s.kills = append(s.kills, next.kills...)
+			if len(s.kills) > killFeedLines {
+				s.kills = s.kills[len(s.kills)-killFeedLines:]
+			}
+		}
+	}
+}
+
+// fetchKills pages through the deaths since the last one seen
+func (s *spectator) fetchKills() error {
+	const limit = 1000
+	for {
+		events, next, err := api.Events(context.Background(), server.EventFilter{Action: server.ActionDeath}, s.since, limit)
+		if err != nil {
+			return err
+		}
+		s.kills = append(s.kills, events...)
+		if next > s.since {
+			s.since = next
+		}
+		if len(events) < limit {
+			return nil
+		}
+	}
+}
+
+// draw renders the whole board with the leaderboard and kill feed alongside
+func (s *spectator) draw() {
+	termbox.Clear(termbox.ColorDefault, termbox.ColorDefault)
+	b := s.board
+
+	printAt(0, 0, termbox.AttrReverse, fmt.Sprintf("spectating  round %d  tick %d  %d robots", b.Round, b.Tick, len(b.Robots)))
+	if s.err != nil {
+		printAt(0, 1, termbox.ColorRed|termbox.AttrBold, "lost the server: "+s.err.Error())
+	}
+
+	for y := 0; y < b.Grid; y++ {
+		for x := 0; x < b.Grid; x++ {
+			termbox.SetCell(x*2+1, y+2, '·', termbox.ColorDefault, termbox.ColorDefault)
+		}
+	}
+	// The living are drawn over the dead
+	robots := append([]server.Robot{}, b.Robots...)
+	sort.SliceStable(robots, func(i, j int) bool { return robots[i].Dead && !robots[j].Dead })
+	for _, r := range robots {
+		fg, ch := colorOf(r.Color)|termbox.AttrBold, arrows[r.Direction]
+		if r.Dead {
+			fg, ch = termbox.ColorDefault, '✗'
+		}
+		termbox.SetCell(r.X*2, r.Y+2, []rune(r.Name + " ")[0], fg, termbox.ColorDefault)
+		termbox.SetCell(r.X*2+1, r.Y+2, ch, fg, termbox.ColorDefault)
+	}
+
+	// Panel to the right of the grid
+	x, y := b.Grid*2+3, 2
+	line := func(fg termbox.Attribute, format string, args ...interface{}) {
+		printAt(x, y, fg, fmt.Sprintf(format, args...))
+		y++
+	}
+
+	line(termbox.AttrUnderline, "leaderboard")
+	sort.SliceStable(robots, func(i, j int) bool { return robots[i].Score > robots[j].Score })
+	for i, r := range robots {
+		status := ""
+		if r.Dead {
+			status = " dead"
+		}
+		line(colorOf(r.Color), "%2d. %-4s %5d%s", i+1, r.Name, r.Score, status)
+	}
+	y++
+
+	line(termbox.AttrUnderline, "kills")
+	for i := len(s.kills) - 1; i >= 0; i-- {
+		k := s.kills[i]
+		line(termbox.ColorDefault, "%s  round %-3d %s killed %s", k.Time.Local().Format("15:04:05"), k.Round, k.Actor, k.Target)
+	}
+
+	printAt(0, b.Grid+3, termbox.ColorDefault, "esc quit")
+	termbox.Flush()
+}
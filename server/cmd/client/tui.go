@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fanatic/robot-game/server"
+	"github.com/fanatic/robot-game/server/client"
+	"github.com/nsf/termbox-go"
+)
+
+// refreshEvery is how often the screen is brought up to date with the server
+const refreshEvery = 250 * time.Millisecond
+
+// logLines is how many of the latest actions the log keeps
+const logLines = 12
+
+// tui draws the arena as the player's robot sees it and sends its actions
+type tui struct {
+	robot   *server.Robot
+	state   server.State
+	log     []string
+	pending string    // action waiting on the server, if any
+	sent    time.Time // when it was sent
+	err     error     // why the last refresh failed
+}
+
+// actionResult is how the server answered an action
+type actionResult struct {
+	action string
+	robot  *server.Robot
+	err    error
+}
+
+// refreshResult is the latest from the server
+type refreshResult struct {
+	robot *server.Robot
+	state *server.State
+	err   error
+}
+
+// play runs the TUI until esc is pressed
+func play(r *server.Robot) error {
+	t := &tui{robot: r, state: server.State{Grid: 16}}
+	t.logf("joined as %s", r.Name)
+
+	events, stop := pollEvents()
+	defer stop()
+
+	results := make(chan actionResult)
+	refreshed := make(chan refreshResult)
+	refreshing := false
+	ticker := time.NewTicker(refreshEvery)
+	defer ticker.Stop()
+
+	for {
+		t.draw()
+
+		select {
+		case ev := <-events:
+			switch ev.Type {
+			case termbox.EventKey:
+				switch ev.Key {
+				case termbox.KeyArrowUp:
+					t.send(results, "move", api.Move)
+				case termbox.KeyArrowLeft:
+					t.send(results, "turn-left", func(ctx context.Context, id string) (*server.Robot, error) { return api.Turn(ctx, id, true) })
+				case termbox.KeyArrowRight:
+					t.send(results, "turn-right", func(ctx context.Context, id string) (*server.Robot, error) { return api.Turn(ctx, id, false) })
+				case termbox.KeySpace:
+					t.send(results, "attack", api.Attack)
+				case termbox.KeyEsc:
+					return nil
+				}
+			case termbox.EventError:
+				return ev.Err
+			}
+
+		case res := <-results:
+			t.pending = ""
+			if res.err != nil {
+				t.logf("%-10s %v", res.action, res.err)
+				continue
+			}
+			t.robot = res.robot
+			t.logf("%-10s ok", res.action)
+
+		case <-ticker.C:
+			if refreshing {
+				continue
+			}
+			refreshing = true
+			go func(id string) {
+				ctx := context.Background()
+				var res refreshResult
+				if res.state, res.err = api.State(ctx); res.err == nil {
+					res.robot, res.err = api.Robot(ctx, id)
+				}
+				refreshed <- res
+			}(t.robot.ID)
+
+		case res := <-refreshed:
+			refreshing = false
+			if errors.Is(res.err, client.ErrDead) {
+				// The dead stay where they fell until the next round
+				t.robot.Dead = true
+				res.err = nil
+			}
+			if t.err = res.err; res.err != nil {
+				continue
+			}
+			if res.state.Round != t.state.Round {
+				t.logf("round %d started", res.state.Round)
+			}
+			t.state = *res.state
+			if res.robot != nil {
+				t.robot = res.robot
+			}
+		}
+	}
+}
+
+// send makes an action in the background, one at a time
+func (t *tui) send(results chan<- actionResult, action string, do func(ctx context.Context, id string) (*server.Robot, error)) {
+	if t.pending != "" {
+		t.logf("%-10s still waiting on %s", action, t.pending)
+		return
+	}
+	t.pending, t.sent = action, time.Now()
+
+	id := t.robot.ID
+	go func() {
+		res := actionResult{action: action}
+		res.robot, res.err = do(context.Background(), id)
+		results <- res
+	}()
+}
+
+func (t *tui) logf(format string, args ...interface{}) {
+	t.log = append(t.log, time.Now().Format("15:04:05 ")+fmt.Sprintf(format, args...))
+	if len(t.log) > logLines {
+		t.log = t.log[len(t.log)-logLines:]
+	}
+}
+
+// draw renders the status bar, the grid as far as the robot can see, what's in range and the log
+func (t *tui) draw() {
+	termbox.Clear(termbox.ColorDefault, termbox.ColorDefault)
+	r := t.robot
+
+	status := fmt.Sprintf("%s  score %d  round %d  tick %d  x=%d y=%d  ", r.Name, r.Score, t.state.Round, t.state.Tick, r.X, r.Y)
+	if r.Dead {
+		status += "dead until the round ends  "
+	}
+	printAt(0, 0, termbox.AttrReverse, status+t.cooldown())
+	if t.err != nil {
+		printAt(0, 1, termbox.ColorRed|termbox.AttrBold, "lost the server: "+t.err.Error())
+	}
+
+	// Two columns per cell like the replay, only the cells next to the robot can be seen
+	for y := 0; y < t.state.Grid; y++ {
+		for x := 0; x < t.state.Grid; x++ {
+			if abs(x-r.X)+abs(y-r.Y) <= 1 {
+				termbox.SetCell(x*2+1, y+2, '·', termbox.ColorDefault, termbox.ColorDefault)
+			} else {
+				termbox.SetCell(x*2+1, y+2, ' ', termbox.ColorDefault, termbox.ColorDefault)
+			}
+		}
+	}
+	for _, other := range r.InRange {
+		termbox.SetCell(other.X*2, other.Y+2, []rune(other.Name + " ")[0], termbox.ColorRed|termbox.AttrBold, termbox.ColorDefault)
+		termbox.SetCell(other.X*2+1, other.Y+2, arrows[other.Direction], termbox.ColorRed|termbox.AttrBold, termbox.ColorDefault)
+	}
+	fg, ch := colorOf(r.Color)|termbox.AttrBold, arrows[r.Direction]
+	if r.Dead {
+		fg, ch = termbox.ColorDefault, '✗'
+	}
+	termbox.SetCell(r.X*2, r.Y+2, []rune(r.Name + " ")[0], fg, termbox.ColorDefault)
+	termbox.SetCell(r.X*2+1, r.Y+2, ch, fg, termbox.ColorDefault)
+
+	// Panel to the right of the grid
+	x, y := t.state.Grid*2+3, 2
+	printAt(x, y, termbox.AttrUnderline, "in range")
+	y++
+	if len(r.InRange) == 0 {
+		printAt(x, y, termbox.ColorDefault, "nobody")
+		y++
+	}
+	for _, other := range r.InRange {
+		printAt(x, y, termbox.ColorRed, fmt.Sprintf("%-4s x=%d y=%d", other.Name, other.X, other.Y))
+		y++
+	}
+	y++
+	printAt(x, y, termbox.AttrUnderline, "log")
+	y++
+	for _, line := range t.log {
+		printAt(x, y, termbox.ColorDefault, line)
+		y++
+	}
+
+	printAt(0, t.state.Grid+3, termbox.ColorDefault, "↑ move  ←/→ turn  space attack  esc quit")
+	termbox.Flush()
+}
+
+// cooldown shows how long is left before the pending action lands
+func (t *tui) cooldown() string {
+	if t.pending == "" || t.state.CurrentDelay <= 0 {
+		return "ready"
+	}
+	const width = 10
+	done := int(time.Since(t.sent) * width / t.state.CurrentDelay)
+	if done > width {
+		done = width
+	}
+	bar := []rune("[..........]")
+	for i := 0; i < done; i++ {
+		bar[i+1] = '#'
+	}
+	return t.pending + " " + string(bar)
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
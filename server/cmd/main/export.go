@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"os"
+
+	"github.com/fanatic/robot-game/server"
+)
+
+var errUsage = errors.New("usage")
+
+// export writes the game to the file in args, or stdout
+func export(g *server.Game, args []string) error {
+	if len(args) > 1 {
+		return errUsage
+	}
+
+	e, err := g.Export()
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if len(args) == 1 {
+		f, err := os.Create(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(e)
+}
+
+// importFile replaces the game with the export in args
+func importFile(g *server.Game, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var e server.Export
+	if err := json.NewDecoder(f).Decode(&e); err != nil {
+		return err
+	}
+	if err := g.Import(&e); err != nil {
+		return err
+	}
+
+	log.Printf("import at=finish round=%d robots=%d\n", e.Round, len(e.Robots))
+	return nil
+}
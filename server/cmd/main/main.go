@@ -2,27 +2,157 @@ package main
 
 import (
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 
 	"github.com/fanatic/robot-game/server"
+	"github.com/fanatic/robot-game/server/gym"
 )
 
+const usage = `Usage:
+  main                 serve the game
+  main export [FILE]   write the game in my.db to FILE, or stdout
+  main import FILE     replace the game in my.db with FILE
+  main verify FILE     check the replay in FILE plays out as recorded
+
+export and import open my.db themselves, so they wait for a running server to let go of it. Stop the server
+first, or use GET /admin/export and POST /admin/import on the running server instead.`
+
 func main() {
-	g, err := server.NewGame("my.db")
+	if len(os.Args) > 1 && os.Args[1] == "verify" {
+		if err := verify(os.Args[2:]); err != nil {
+			if err == errUsage {
+				log.Fatalln(usage)
+			}
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 {
+		log.Println("opening my.db, a running server has to let go of it first")
+	}
+	store, err := server.OpenStormStore("my.db")
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	opts := []server.Option{server.WithStore(store), server.WithAdminToken(os.Getenv("ADMIN_TOKEN"))}
+	if population := os.Getenv("NPC_POPULATION"); population != "" {
+		minimum, err := strconv.Atoi(population)
+		if err != nil {
+			log.Fatalf("NPC_POPULATION must be a number: %v\n", err)
+		}
+		difficulty := os.Getenv("NPC_DIFFICULTY")
+		if difficulty == "" {
+			difficulty = server.NPCWanderer
+		}
+		opts = append(opts, server.WithPopulation(minimum, difficulty))
+	}
+	if history := os.Getenv("HISTORY_ROUNDS"); history != "" {
+		rounds, err := strconv.Atoi(history)
+		if err != nil {
+			log.Fatalf("HISTORY_ROUNDS must be a number: %v\n", err)
+		}
+		opts = append(opts, server.WithHistory(rounds))
+	}
+
+	g, err := server.NewGame(opts...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "export":
+			err = export(g, os.Args[2:])
+		case "import":
+			err = importFile(g, os.Args[2:])
+		default:
+			err = errUsage
+		}
+		if closeErr := g.Close(); err == nil {
+			err = closeErr
+		}
+		if err == errUsage {
+			log.Fatalln(usage)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	r, err := server.New(g)
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	if tcpPort := os.Getenv("TCP_PORT"); tcpPort != "" {
+		l, err := net.Listen("tcp", ":"+tcpPort)
+		if err != nil {
+			log.Fatal(err)
+		}
+		go func() {
+			log.Fatal(g.ServeTCP(l))
+		}()
+	}
+
+	if sshPort := os.Getenv("SSH_PORT"); sshPort != "" {
+		hostKey, err := loadHostKey(os.Getenv("SSH_HOST_KEY"))
+		if err != nil {
+			log.Fatal(err)
+		}
+		l, err := net.Listen("tcp", ":"+sshPort)
+		if err != nil {
+			log.Fatal(err)
+		}
+		go func() {
+			log.Fatal(g.ServeSSH(l, hostKey))
+		}()
+	}
+
+	if grpcPort := os.Getenv("GRPC_PORT"); grpcPort != "" {
+		l, err := net.Listen("tcp", ":"+grpcPort)
+		if err != nil {
+			log.Fatal(err)
+		}
+		go func() {
+			log.Fatal(server.NewGRPC(g).Serve(l))
+		}()
+	}
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8000"
 	}
 
-	log.Fatal(http.ListenAndServe(":"+port, r))
+	// Training environments get arenas of their own, they never touch g, but each is a whole game so they're only
+	// served when asked for
+	mux := http.NewServeMux()
+	if os.Getenv("GYM") != "" {
+		environments := gym.NewHandler()
+		defer environments.Close()
+		mux.Handle("/gym/", environments)
+	}
+	mux.Handle("/", r)
+
+	// Stopping the server closes the gym's environments and lets the journal catch up
+	srv := &http.Server{Addr: ":" + port, Handler: mux}
+	go func() {
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+		<-stop
+		srv.Close()
+	}()
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
+	if err := g.Close(); err != nil {
+		log.Println(err)
+	}
 }
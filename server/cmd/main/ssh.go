@@ -0,0 +1,29 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"log"
+	"os"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// loadHostKey reads the SSH server's private key from path, made with ssh-keygen -t ed25519 -f PATH -N "". Without
+// one a new key is made each start, and players' clients will warn them it changed.
+func loadHostKey(path string) (ssh.Signer, error) {
+	if path == "" {
+		log.Println("SSH_HOST_KEY isn't set, using a throwaway host key")
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		return ssh.NewSignerFromKey(key)
+	}
+
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ssh.ParsePrivateKey(pem)
+}
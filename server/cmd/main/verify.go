@@ -0,0 +1,33 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+
+	"github.com/fanatic/robot-game/server"
+)
+
+// verify replays the replay file in args and checks it ends as recorded
+func verify(args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r server.Replay
+	if err := json.NewDecoder(f).Decode(&r); err != nil {
+		return err
+	}
+	if err := r.Verify(); err != nil {
+		return err
+	}
+
+	log.Printf("verify at=finish round=%d actions=%d result=ok\n", r.Round, len(r.Actions))
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/fanatic/robot-game/server/bot"
+	"github.com/fanatic/robot-game/server/sim"
+)
+
+func main() {
+	matches := flag.Int("matches", 100, "how many matches to play")
+	seed := flag.Int64("seed", 1, "seed of the first match, each after adds one")
+	maxActions := flag.Int("max-actions", 500, "actions each bot gets before a match is a draw")
+	bots := flag.String("bots", "random,wall,hunter", "comma separated bots to play: random, wall, hunter, or the command line of a program to run, see bot.Process")
+	timeout := flag.Duration("timeout", time.Second, "how long programs have to answer each turn")
+	flag.Parse()
+
+	s := sim.New(sim.Config{Matches: *matches, Seed: *seed, MaxActions: *maxActions})
+	for i, name := range strings.Split(*bots, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			flag.Usage()
+			os.Exit(2)
+		}
+		// RA, WB, HC and so on, P for programs
+		newBrain, exists := bot.Brains[name]
+		initials := fmt.Sprintf("%c%c", unicode.ToUpper(rune(name[0])), 'A'+i%26)
+		if !exists {
+			command := strings.Fields(name)
+			p, err := bot.NewProcess(*timeout, command[0], command[1:]...)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "unknown bot %q: it isn't one of %s, and it can't be run as a program: %v\n", name, knownBots(), err)
+				flag.Usage()
+				os.Exit(2)
+			}
+			initials = fmt.Sprintf("P%c", 'A'+i%26)
+			// The program is stopped after each match and started again for the next
+			newBrain = func(int64) bot.Brain { return p }
+		}
+		if err := s.Register(initials, newBrain); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	results, err := s.Run()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := results.WriteTable(os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// knownBots lists the bots built in, for when -bots names one that isn't
+func knownBots() string {
+	var names []string
+	for name := range bot.Brains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
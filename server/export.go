@@ -0,0 +1,208 @@
+package server
+
+import (
+	"fmt"
+	"time"
+)
+
+// exportVersion is bumped whenever Export changes in a way older servers can't read
+const exportVersion = 1
+
+// Export is a portable copy of a whole game, for backups and for reproducing bug reports on a fresh server. It
+// includes each robot's ID, which is also its secret, so treat it like a password file.
+type Export struct {
+	Version    int             `json:"version"`
+	ExportedAt time.Time       `json:"exported_at"`
+	Settings   ExportSettings  `json:"settings"`
+	Round      int             `json:"round"`
+	Seed       int64           `json:"seed"`
+	Map        ExportMap       `json:"map"`
+	Robots     []Robot         `json:"robots"`
+	Accounts   []ExportAccount `json:"accounts"`
+}
+
+// ExportSettings are the rules the game was played under
+type ExportSettings struct {
+	Grid            int           `json:"grid"`
+	Delay           time.Duration `json:"delay"`
+	RobotLimit      int           `json:"robot_limit"`
+	SpawnProtection int           `json:"spawn_protection"`
+	Population      *Population   `json:"population,omitempty"`
+}
+
+// ExportAccount is an SSH player's account, robot ID and all
+type ExportAccount struct {
+	Key       string    `json:"key"`
+	Name      string    `json:"name"`
+	RobotID   string    `json:"robot_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ExportMap is the layout of the grid
+type ExportMap struct {
+	SpawnPoints []Location `json:"spawn_points"`
+}
+
+// Export copies the whole game
+func (g *Game) Export() (*Export, error) {
+	w := g.world
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	accounts, err := g.store.Accounts()
+	if err != nil {
+		return nil, err
+	}
+	exported := []ExportAccount{}
+	for _, a := range accounts {
+		exported = append(exported, ExportAccount{Key: a.Key, Name: a.Name, RobotID: a.RobotID, CreatedAt: a.CreatedAt})
+	}
+
+	return &Export{
+		Version:    exportVersion,
+		ExportedAt: g.clock.Now(),
+		Settings: ExportSettings{
+			Grid:            w.state.Grid,
+			Delay:           actionDelay,
+			RobotLimit:      robotLimit,
+			SpawnProtection: spawnProtection,
+			Population:      w.state.Population,
+		},
+		Round:    w.state.Round,
+		Seed:     w.state.Seed,
+		Map:      ExportMap{SpawnPoints: w.state.SpawnPoints},
+		Robots:   w.copyRobots(),
+		Accounts: exported,
+	}, nil
+}
+
+// Validate checks an export could be loaded into this server
+func (e *Export) Validate() error {
+	if e.Version != exportVersion {
+		return fmt.Errorf("unsupported export version %d, expected %d", e.Version, exportVersion)
+	}
+	if e.Settings.Grid != gridSize {
+		return fmt.Errorf("export is for a %d grid but this server plays on %d", e.Settings.Grid, gridSize)
+	}
+	if e.Round < 0 {
+		return fmt.Errorf("round can't be negative")
+	}
+
+	if err := validateSpawnPoints(e.Map.SpawnPoints); err != nil {
+		return err
+	}
+	if e.Settings.Population != nil {
+		if err := validatePopulation(*e.Settings.Population); err != nil {
+			return err
+		}
+	}
+
+	if err := validateRobots(e.Robots); err != nil {
+		return err
+	}
+
+	keys := map[string]bool{}
+	for _, a := range e.Accounts {
+		if a.Key == "" {
+			return fmt.Errorf("%s's account has no key", a.Name)
+		}
+		if keys[a.Key] {
+			return fmt.Errorf("account %s appears twice", a.Key)
+		}
+		keys[a.Key] = true
+	}
+	return nil
+}
+
+// validateRobots checks robots could all be on the grid together
+func validateRobots(robots []Robot) error {
+	onGrid := func(l Location) bool { return l.X >= 0 && l.X < gridSize && l.Y >= 0 && l.Y < gridSize }
+
+	ids := map[string]bool{}
+	names := map[string]int{}
+	cells := map[Location]string{}
+	for _, r := range robots {
+		if r.ID == "" {
+			return fmt.Errorf("robot %q has no id", r.Name)
+		}
+		if ids[r.ID] {
+			return fmt.Errorf("robot id %s appears twice", r.ID)
+		}
+		ids[r.ID] = true
+
+		if len(r.Name) != 2 {
+			return fmt.Errorf("robot %s's name must be exactly 2 characters", r.ID)
+		}
+		if names[r.Name]++; names[r.Name] > robotLimit {
+			return fmt.Errorf("%s has more robots than the limit of %d", r.Name, robotLimit)
+		}
+
+		l := Location{X: r.X, Y: r.Y}
+		if !onGrid(l) {
+			return fmt.Errorf("robot %s is off the grid at %d,%d", r.ID, r.X, r.Y)
+		}
+		if r.Direction < North || r.Direction > West {
+			return fmt.Errorf("robot %s has unknown direction %d", r.ID, r.Direction)
+		}
+		if r.Dead {
+			continue
+		}
+		if other, taken := cells[l]; taken {
+			return fmt.Errorf("robots %s and %s are both at %d,%d", other, r.ID, r.X, r.Y)
+		}
+		cells[l] = r.ID
+	}
+	return nil
+}
+
+// Import replaces the whole game with an export
+func (g *Game) Import(e *Export) error {
+	if err := e.Validate(); err != nil {
+		return err
+	}
+
+	w := g.world
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	s := w.state
+	s.Round = e.Round
+	s.Seed = e.Seed
+	s.SpawnPoints = e.Map.SpawnPoints
+	s.Population = e.Settings.Population
+
+	ev := &Event{Action: ActionImport, Robots: e.Robots, State: &s}
+	keep := map[string]bool{}
+	for _, r := range e.Robots {
+		keep[r.ID] = true
+	}
+	for _, r := range w.robots {
+		if !keep[r.ID] {
+			ev.Deleted = append(ev.Deleted, r.ID)
+		}
+	}
+
+	if err := g.apply(ev); err != nil {
+		return err
+	}
+	g.startNPCs()
+
+	// Accounts are brought over with their robots. Any whose robot didn't come along, or has just been removed,
+	// start over with a new one when they next log in.
+	accounts, err := g.store.Accounts()
+	if err != nil {
+		return err
+	}
+	for _, a := range e.Accounts {
+		accounts = append(accounts, Account{Key: a.Key, Name: a.Name, RobotID: a.RobotID, CreatedAt: a.CreatedAt})
+	}
+	for i := range accounts {
+		if !keep[accounts[i].RobotID] {
+			accounts[i].RobotID = ""
+		}
+		if err := g.store.SaveAccount(&accounts[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
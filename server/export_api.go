@@ -0,0 +1,40 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+func getExport(g *Game, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	return g.Export()
+}
+
+func postImport(g *Game, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	var e Export
+	if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+		return nil, fmt.Errorf("invalid payload body, expected an export: %v", err)
+	}
+
+	if err := g.Import(&e); err != nil {
+		return nil, err
+	}
+
+	return getState(g, w, r)
+}
+
+func getSpawnPoints(g *Game, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	return g.SpawnPoints(), nil
+}
+
+func putSpawnPoints(g *Game, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	var points []Location
+	if err := json.NewDecoder(r.Body).Decode(&points); err != nil {
+		return nil, fmt.Errorf("invalid payload body [{\"x\": 0, \"y\": 0}, ...]")
+	}
+
+	if err := g.SetSpawnPoints(points); err != nil {
+		return nil, err
+	}
+	return g.SpawnPoints(), nil
+}
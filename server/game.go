@@ -1,24 +1,193 @@
 package server
 
 import (
-	"github.com/asdine/storm"
+	"fmt"
+	"sync"
+	"time"
 )
 
 // Game holds variables used for lifetime of the API process
 type Game struct {
-	db *storm.DB
+	store   Store
+	journal journal
+	world   *World
+	seed    *int64
+	clock   Clock
+
+	history   int // finished rounds to keep the journal for, 0 keeps them all
+	compacted int // the journal has been dropped up to this event
+
+	spawnPoints []Location // replace the map's spawn points when not nil
+
+	adminToken string
+	population *Population // replaces the stored population when not nil
+	npcs       bool        // whether npcLoop is running, guarded by the world's lock
+	noLoops    bool        // nothing runs in the background
+
+	watch   sync.Mutex
+	changed chan struct{} // closed by the next event, see changes
+
+	done      chan struct{}
+	loops     sync.WaitGroup
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// Option configures a Game
+type Option func(*Game)
+
+// WithSeed starts the current round over with seed, making its spawns and the rounds after it reproducible
+func WithSeed(seed int64) Option {
+	return func(g *Game) {
+		g.seed = &seed
+	}
+}
+
+// WithStore sets where the game is persisted. Without it the game only lives in memory.
+func WithStore(s Store) Option {
+	return func(g *Game) {
+		g.store = s
+	}
+}
+
+// WithClock replaces the wall clock, e.g. with a FakeClock so actions don't sleep
+func WithClock(c Clock) Option {
+	return func(g *Game) {
+		g.clock = c
+	}
+}
+
+// WithHistory keeps the journal, and so the events and replays, of the last rounds finished rounds. Older ones are
+// dropped as snapshots are taken. 0 keeps every round.
+func WithHistory(rounds int) Option {
+	return func(g *Game) {
+		g.history = rounds
+	}
+}
+
+// WithAdminToken enables the /admin endpoints for requests bearing token
+func WithAdminToken(token string) Option {
+	return func(g *Game) {
+		g.adminToken = token
+	}
+}
+
+// WithPopulation keeps at least minimum robots on the grid, adding NPCs of difficulty when players are short
+func WithPopulation(minimum int, difficulty string) Option {
+	return func(g *Game) {
+		g.population = &Population{Minimum: minimum, Difficulty: difficulty}
+	}
 }
 
-// NewGame returns a Game struct with db handle
-func NewGame(dbPath string) (*Game, error) {
-	db, err := storm.Open(dbPath)
-	if err != nil {
+// WithoutLoops runs nothing in the background, for simulations that drive the game themselves. The journal is only
+// written when events are read and the game is closed, snapshots are only taken on closing, and NPCs don't play.
+func WithoutLoops() Option {
+	return func(g *Game) {
+		g.noLoops = true
+	}
+}
+
+// WithSpawnPoints makes robots (re)enter the grid at points, furthest from everyone else first, rather than anywhere
+func WithSpawnPoints(points ...Location) Option {
+	return func(g *Game) {
+		g.spawnPoints = append([]Location{}, points...)
+	}
+}
+
+// NewGame returns a Game restored from its store
+func NewGame(opts ...Option) (*Game, error) {
+	g := &Game{
+		store:   NewMemoryStore(),
+		journal: journal{wake: make(chan struct{}, 1)},
+		clock:   realClock{},
+		history: historyRounds,
+		done:    make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	if g.population != nil {
+		if err := validatePopulation(*g.population); err != nil {
+			g.store.Close()
+			return nil, err
+		}
+	}
+	if err := validateSpawnPoints(g.spawnPoints); err != nil {
+		g.store.Close()
+		return nil, err
+	}
+	if g.history < 0 {
+		g.store.Close()
+		return nil, fmt.Errorf("history can't be negative")
+	}
+
+	if m, ok := g.store.(Migrator); ok {
+		if err := m.Migrate(); err != nil {
+			g.store.Close()
+			return nil, err
+		}
+	}
+
+	if err := g.recover(); err != nil {
+		g.store.Close()
 		return nil, err
 	}
-	return &Game{db: db}, nil
+
+	// Pick up the round's seed where it was recorded, otherwise record one so the round can be reproduced
+	s := g.world.state
+	if g.seed != nil || s.Seed == 0 {
+		s.Seed = time.Now().UnixNano()
+		if g.seed != nil {
+			s.Seed = *g.seed
+		}
+		if err := g.apply(&Event{Action: ActionSeed, State: &s}); err != nil {
+			g.store.Close()
+			return nil, err
+		}
+	}
+	if g.spawnPoints != nil {
+		if err := g.setSpawnPoints(g.spawnPoints); err != nil {
+			g.store.Close()
+			return nil, err
+		}
+	}
+
+	if g.population != nil {
+		if err := g.setPopulation(*g.population); err != nil {
+			g.store.Close()
+			return nil, err
+		}
+	}
+
+	if g.noLoops {
+		return g, nil
+	}
+	g.loops.Add(2)
+	go g.journalLoop()
+	go g.snapshotLoop()
+
+	// NPCs left over from before a restart carry on
+	g.world.mu.Lock()
+	g.startNPCs()
+	g.world.mu.Unlock()
+	return g, nil
 }
 
-// Close a db
+// Close writes out the journal, takes a final snapshot and closes the store. Closing again returns the same.
 func (g *Game) Close() error {
-	return g.db.Close()
+	g.closeOnce.Do(func() {
+		// Under the world's lock so no loop is started once it's waited for
+		g.world.mu.Lock()
+		close(g.done)
+		g.world.mu.Unlock()
+		g.loops.Wait()
+
+		if err := g.snapshot(); err != nil {
+			g.store.Close()
+			g.closeErr = err
+			return
+		}
+		g.closeErr = g.store.Close()
+	})
+	return g.closeErr
 }
@@ -0,0 +1,183 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fanatic/robot-game/server/pb"
+	"github.com/fanatic/robot-game/server/wire"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// NewGRPC returns a gRPC server for g, to serve alongside the HTTP API. Its Observe stream sends every new tick as it
+// happens.
+func NewGRPC(g *Game, opts ...grpc.ServerOption) *grpc.Server {
+	s := grpc.NewServer(opts...)
+	pb.RegisterRobotGameServer(s, &grpcServer{g: g})
+	return s
+}
+
+// grpcServer answers each RPC with the same Game methods the HTTP handlers use
+type grpcServer struct {
+	pb.UnimplementedRobotGameServer
+	g *Game
+}
+
+func (s *grpcServer) Join(ctx context.Context, req *pb.JoinRequest) (*pb.Robot, error) {
+	name, err := PlayerName(req.Name)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	r, err := s.g.NewRobot(name)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return pbRobot(r), nil
+}
+
+func (s *grpcServer) Resume(ctx context.Context, req *pb.RobotRequest) (*pb.Robot, error) {
+	r, err := s.g.ResumeRobot(req.Id)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return pbRobot(r), nil
+}
+
+func (s *grpcServer) GetRobot(ctx context.Context, req *pb.RobotRequest) (*pb.Robot, error) {
+	return s.robot(req.Id)
+}
+
+func (s *grpcServer) Leave(ctx context.Context, req *pb.RobotRequest) (*pb.LeaveResponse, error) {
+	if err := s.g.DeleteRobot(req.Id); err != nil {
+		return nil, grpcError(err)
+	}
+	return &pb.LeaveResponse{}, nil
+}
+
+func (s *grpcServer) Move(ctx context.Context, req *pb.RobotRequest) (*pb.Robot, error) {
+	if err := s.g.Move(req.Id); err != nil {
+		return nil, grpcError(err)
+	}
+	return s.robot(req.Id)
+}
+
+func (s *grpcServer) Turn(ctx context.Context, req *pb.TurnRequest) (*pb.Robot, error) {
+	if err := s.g.Turn(req.Id, req.Left); err != nil {
+		return nil, grpcError(err)
+	}
+	return s.robot(req.Id)
+}
+
+func (s *grpcServer) Attack(ctx context.Context, req *pb.RobotRequest) (*pb.Robot, error) {
+	if err := s.g.Attack(req.Id); err != nil {
+		return nil, grpcError(err)
+	}
+	return s.robot(req.Id)
+}
+
+func (s *grpcServer) GetState(ctx context.Context, req *pb.StateRequest) (*pb.State, error) {
+	state, err := s.g.State()
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	out := &pb.State{
+		Round:      int32(state.Round),
+		Tick:       int32(state.Tick),
+		Grid:       int32(state.Grid),
+		Delay:      durationpb.New(state.CurrentDelay),
+		RobotLimit: int32(state.CurrentRobotLimit),
+	}
+	for _, l := range state.SpawnPoints {
+		out.SpawnPoints = append(out.SpawnPoints, &pb.Location{X: int32(l.X), Y: int32(l.Y)})
+	}
+	for i := range state.Robots {
+		r := pbRobot(&state.Robots[i])
+		r.Id = ""
+		out.Robots = append(out.Robots, r)
+	}
+	return out, nil
+}
+
+func (s *grpcServer) Observe(req *pb.RobotRequest, stream grpc.ServerStreamingServer[pb.Observation]) error {
+	tick := -1
+	for {
+		// Taken before looking, so nothing that happens in between is missed
+		changed := s.g.changes()
+		state, err := s.g.State()
+		if err != nil {
+			return grpcError(err)
+		}
+		if state.Tick != tick {
+			tick = state.Tick
+			// Dead or alive, so the robot can watch for the next round
+			r, err := s.g.ResumeRobot(req.Id)
+			if err != nil {
+				return grpcError(err)
+			}
+			obs := &pb.Observation{Round: int32(state.Round), Tick: int32(state.Tick), Grid: int32(state.Grid), Robot: pbRobot(r)}
+			if err := stream.Send(obs); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-changed:
+		case <-stream.Context().Done():
+			return nil
+		case <-s.g.done:
+			return status.Error(codes.Unavailable, "the game is shutting down")
+		}
+	}
+}
+
+// robot is the living robot with id
+func (s *grpcServer) robot(id string) (*pb.Robot, error) {
+	r, err := s.g.Robot(id)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return pbRobot(r), nil
+}
+
+// refusals are the game turning an action down, rather than anything going wrong
+var refusals = []error{wire.ErrDead, wire.ErrLimit, wire.ErrGridFull, wire.ErrOffGrid, wire.ErrBlocked, wire.ErrMissed, wire.ErrProtected}
+
+// grpcError gives err the status code closest to what it means. The game turning an action down is a
+// FailedPrecondition, anything else, like the store failing, is Internal.
+func grpcError(err error) error {
+	if errors.Is(err, wire.ErrNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	for _, refusal := range refusals {
+		if errors.Is(err, refusal) {
+			return status.Error(codes.FailedPrecondition, err.Error())
+		}
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func pbRobot(r *Robot) *pb.Robot {
+	out := &pb.Robot{
+		Id:             r.ID,
+		CreatedAt:      timestamppb.New(r.CreatedAt),
+		Name:           r.Name,
+		X:              int32(r.X),
+		Y:              int32(r.Y),
+		Color:          r.Color,
+		Direction:      pb.Direction(r.Direction),
+		Vision:         int32(r.Vision),
+		Score:          int32(r.Score),
+		Dead:           r.Dead,
+		ProtectedUntil: timestamppb.New(r.ProtectedUntil),
+		Npc:            r.NPC,
+	}
+	for _, other := range r.InRange {
+		out.RobotsInRange = append(out.RobotsInRange, &pb.ShortRobot{Name: other.Name, X: int32(other.X), Y: int32(other.Y), Direction: pb.Direction(other.Direction)})
+	}
+	return out
+}
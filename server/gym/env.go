@@ -0,0 +1,246 @@
+// Package gym wraps a private arena in a Gym-style environment for training agents: Reset it with a seed, then Step
+// it one action at a time for an observation, a reward and whether the episode is done. Nothing waits between
+// actions, so episodes run as fast as the agent can decide.
+package gym
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/fanatic/robot-game/server"
+	"github.com/fanatic/robot-game/server/bot"
+	"github.com/fanatic/robot-game/server/sim"
+)
+
+// agentName is what the agent's robot is called, opponents are OA, OB and so on
+const agentName = "AG"
+
+// Rewards shapes what the agent is paid for. Each is added whenever it happens, so make the bad ones negative.
+type Rewards struct {
+	Kill     float64 `json:"kill"`     // per robot the agent kills
+	Death    float64 `json:"death"`    // when the agent is killed
+	Win      float64 `json:"win"`      // when the agent is the last robot standing
+	Survival float64 `json:"survival"` // every step the agent is still alive after
+	Score    float64 `json:"score"`    // per point of score the agent gains
+}
+
+// Config is how an Env plays
+type Config struct {
+	Opponents []string `json:"opponents"`  // names from bot.Brains, one robot each. Defaults to random, wall and hunter.
+	Rewards   *Rewards `json:"rewards"`    // defaults to 1 a kill, -1 a death and 5 a win
+	MaxSteps  int      `json:"max_steps"`  // steps before an episode is cut short, 500 by default
+	FullBoard bool     `json:"full_board"` // observe every robot, not only those the agent can see
+}
+
+// Info is about the episode so far, for logging rather than learning from
+type Info struct {
+	Steps  int    `json:"steps"`
+	Kills  int    `json:"kills"`
+	Score  int    `json:"score"`
+	Result string `json:"result"`           // "ok", or why the last action failed
+	Winner string `json:"winner,omitempty"` // set once the round is won
+}
+
+// Env is one agent's arena. It isn't safe for concurrent use.
+type Env struct {
+	config    Config
+	opponents []sim.NewBrain
+
+	g      *server.Game
+	agent  string               // robot ID
+	brains map[string]bot.Brain // opponents by robot ID, in join order in ids
+	ids    []string
+	since  int // last event accounted for
+	round  int
+	done   bool
+	info   Info
+}
+
+// New returns an Env, Reset it before stepping
+func New(config Config) (*Env, error) {
+	if config.Opponents == nil {
+		config.Opponents = []string{"random", "wall", "hunter"}
+	}
+	if len(config.Opponents) == 0 || len(config.Opponents) > 9 {
+		return nil, errors.New("there must be between 1 and 9 opponents")
+	}
+	if config.Rewards == nil {
+		config.Rewards = &Rewards{Kill: 1, Death: -1, Win: 5}
+	}
+	if config.MaxSteps == 0 {
+		config.MaxSteps = 500
+	}
+
+	e := &Env{config: config}
+	for _, name := range config.Opponents {
+		newBrain, exists := bot.Brains[name]
+		if !exists {
+			return nil, fmt.Errorf("unknown opponent %q", name)
+		}
+		e.opponents = append(e.opponents, newBrain)
+	}
+	return e, nil
+}
+
+// Reset starts a new episode in a fresh arena. The same seed plays out the same given the same actions.
+func (e *Env) Reset(seed int64) (*Observation, error) {
+	if err := e.Close(); err != nil {
+		return nil, err
+	}
+
+	g, err := server.NewGame(server.WithSeed(seed), server.WithClock(server.NewFakeClock(time.Unix(0, 0).UTC())), server.WithoutLoops())
+	if err != nil {
+		return nil, err
+	}
+	e.g, e.done, e.info, e.since = g, false, Info{}, 0
+
+	r, err := g.NewRobot(agentName)
+	if err != nil {
+		return nil, err
+	}
+	e.agent = r.ID
+
+	seeds := rand.New(rand.NewSource(seed))
+	e.brains, e.ids = map[string]bot.Brain{}, nil
+	for i, newBrain := range e.opponents {
+		r, err := g.NewRobot(fmt.Sprintf("O%c", 'A'+i))
+		if err != nil {
+			return nil, err
+		}
+		e.brains[r.ID] = newBrain(seeds.Int63())
+		e.ids = append(e.ids, r.ID)
+	}
+
+	state, err := g.State()
+	if err != nil {
+		return nil, err
+	}
+	e.round = state.Round
+	// The joins aren't anybody's reward
+	if _, e.since, err = g.Events(server.EventFilter{}, 0, 1<<20); err != nil {
+		return nil, err
+	}
+	return e.observe()
+}
+
+// Step plays action for the agent, then one action for each opponent still alive. The episode is done when the
+// agent dies, the round is won, or MaxSteps is up.
+func (e *Env) Step(action bot.Action) (obs *Observation, reward float64, done bool, info Info, err error) {
+	if e.g == nil {
+		return nil, 0, false, Info{}, errors.New("reset the environment first")
+	}
+	if e.done {
+		return nil, 0, true, e.info, errors.New("the episode is done, reset the environment")
+	}
+
+	e.info.Steps++
+	e.info.Result = result(e.act(e.agent, action))
+
+	for _, id := range e.ids {
+		if e.roundOver() {
+			break
+		}
+		r, err := e.g.Robot(id)
+		if err != nil {
+			// Dead
+			continue
+		}
+		state, err := e.g.State()
+		if err != nil {
+			return nil, 0, false, e.info, err
+		}
+		e.act(id, e.brains[id].Decide(bot.View{Robot: *r, Grid: state.Grid, Round: state.Round, Tick: state.Tick}))
+	}
+
+	reward, err = e.reward()
+	if err != nil {
+		return nil, 0, false, e.info, err
+	}
+	obs, err = e.observe()
+	if err != nil {
+		return nil, 0, false, e.info, err
+	}
+	e.done = e.done || obs.Robot.Dead || e.info.Steps >= e.config.MaxSteps
+	return obs, reward, e.done, e.info, nil
+}
+
+// Close ends the episode and frees the arena
+func (e *Env) Close() error {
+	if e.g == nil {
+		return nil
+	}
+	err := e.g.Close()
+	e.g = nil
+	return err
+}
+
+// act plays an action for the robot id, the error being why it failed
+func (e *Env) act(id string, action bot.Action) error {
+	switch action {
+	case bot.Move:
+		return e.g.Move(id)
+	case bot.TurnLeft:
+		return e.g.Turn(id, true)
+	case bot.TurnRight:
+		return e.g.Turn(id, false)
+	case bot.Attack:
+		return e.g.Attack(id)
+	case bot.Wait:
+		return nil
+	}
+	return fmt.Errorf("unknown action %d", action)
+}
+
+func (e *Env) roundOver() bool {
+	state, err := e.g.State()
+	return err == nil && state.Round != e.round
+}
+
+// reward adds up what happened to the agent since the last step, ending the episode if the round was won
+func (e *Env) reward() (float64, error) {
+	events, next, err := e.g.Events(server.EventFilter{}, e.since, 1<<20)
+	if err != nil {
+		return 0, err
+	}
+	e.since = next
+
+	rw := e.config.Rewards
+	var reward float64
+	dead := false
+	for _, ev := range events {
+		switch {
+		case ev.Action == server.ActionDeath && ev.Actor == agentName:
+			e.info.Kills++
+			reward += rw.Kill
+		case ev.Action == server.ActionDeath && ev.Target == agentName:
+			dead = true
+			reward += rw.Death
+		case ev.Action == server.ActionRound:
+			e.info.Winner = ev.Actor
+			e.done = true
+			if ev.Actor == agentName {
+				reward += rw.Win
+			}
+		}
+	}
+	if !dead {
+		reward += rw.Survival
+	}
+
+	r, err := e.g.ResumeRobot(e.agent)
+	if err != nil {
+		return 0, err
+	}
+	reward += rw.Score * float64(r.Score-e.info.Score)
+	e.info.Score = r.Score
+	return reward, nil
+}
+
+func result(err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return "ok"
+}
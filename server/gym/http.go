@@ -0,0 +1,257 @@
+package gym
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/fanatic/robot-game/server/bot"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// maxEnvs is how many environments the HTTP API keeps at once, each is a whole arena
+const maxEnvs = 64
+
+// idleTimeout is how long an environment is kept without any requests before it's closed to make room
+const idleTimeout = 10 * time.Minute
+
+// Handler serves environments over HTTP for agents that aren't written in Go
+type Handler struct {
+	router http.Handler
+
+	mu     sync.Mutex
+	envs   map[string]*lockedEnv
+	idle   time.Duration
+	closed bool
+}
+
+// lockedEnv lets requests for different environments run at once, and requests for the same one take turns
+type lockedEnv struct {
+	sync.Mutex
+	*Env
+	used time.Time // last request, guarded by the handler's lock
+}
+
+// Option configures the handler
+type Option func(h *Handler)
+
+// WithIdleTimeout closes environments after d without a request, rather than idleTimeout
+func WithIdleTimeout(d time.Duration) Option {
+	return func(h *Handler) {
+		h.idle = d
+	}
+}
+
+// NewHandler returns the environment API, to be mounted at /gym:
+//
+//	POST   /gym/envs            create an environment from a Config, returns its id
+//	POST   /gym/envs/{id}/reset {"seed": 1}, returns the first observation
+//	POST   /gym/envs/{id}/step  {"action": "move"}, returns the observation, reward, done and info
+//	DELETE /gym/envs/{id}
+//
+// Errors come back as {"at":"error","msg":...} like the rest of the API. Environments that go unused for a while are
+// closed as the next request comes in, agents that stop without deleting theirs don't keep them forever. Close the
+// handler to close the rest.
+func NewHandler(opts ...Option) *Handler {
+	h := &Handler{envs: map[string]*lockedEnv{}, idle: idleTimeout}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	r := mux.NewRouter()
+	r.HandleFunc("/gym/envs", h.wrap(h.create)).Methods("POST")
+	r.HandleFunc("/gym/envs/{id}/reset", h.wrap(h.reset)).Methods("POST")
+	r.HandleFunc("/gym/envs/{id}/step", h.wrap(h.step)).Methods("POST")
+	r.HandleFunc("/gym/envs/{id}", h.wrap(h.delete)).Methods("DELETE")
+	h.router = r
+	return h
+}
+
+// ServeHTTP closes idle environments, then serves the request
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.evict()
+	h.router.ServeHTTP(w, r)
+}
+
+// Close closes every environment, and any asked for after are refused
+func (h *Handler) Close() error {
+	h.mu.Lock()
+	var envs []*lockedEnv
+	for _, env := range h.envs {
+		envs = append(envs, env)
+	}
+	h.envs = map[string]*lockedEnv{}
+	h.closed = true
+	h.mu.Unlock()
+
+	return closeEnvs(envs)
+}
+
+func (h *Handler) create(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	var config Config
+	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+		return nil, fmt.Errorf("invalid payload body {\"opponents\": [\"hunter\"], \"rewards\": {\"kill\": 1}, \"max_steps\": 500}")
+	}
+	env, err := New(config)
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		env.Close()
+		return nil, fmt.Errorf("the gym is closed")
+	}
+	if len(h.envs) >= maxEnvs {
+		env.Close()
+		return nil, fmt.Errorf("no more environments - delete one first")
+	}
+	id, _ := uuid.NewRandom()
+	h.envs[id.String()] = &lockedEnv{Env: env, used: time.Now()}
+
+	return struct {
+		ID       string   `json:"id"`
+		Channels int      `json:"channels"`
+		Actions  []string `json:"actions"`
+	}{id.String(), Channels, []string{bot.Wait.String(), bot.Move.String(), bot.TurnLeft.String(), bot.TurnRight.String(), bot.Attack.String()}}, nil
+}
+
+func (h *Handler) reset(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	payload := struct {
+		Seed int64 `json:"seed"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("invalid payload body {\"seed\": 1}")
+	}
+
+	env, err := h.env(r)
+	if err != nil {
+		return nil, err
+	}
+	env.Lock()
+	defer env.Unlock()
+
+	obs, err := env.Reset(payload.Seed)
+	if err != nil {
+		return nil, err
+	}
+	return struct {
+		Observation *Observation `json:"observation"`
+	}{obs}, nil
+}
+
+func (h *Handler) step(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	payload := struct {
+		Action string `json:"action"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("invalid payload body {\"action\": \"move\"}")
+	}
+	action, err := bot.ParseAction(payload.Action)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := h.env(r)
+	if err != nil {
+		return nil, err
+	}
+	env.Lock()
+	defer env.Unlock()
+
+	obs, reward, done, info, err := env.Step(action)
+	if err != nil {
+		return nil, err
+	}
+	return struct {
+		Observation *Observation `json:"observation"`
+		Reward      float64      `json:"reward"`
+		Done        bool         `json:"done"`
+		Info        Info         `json:"info"`
+	}{obs, reward, done, info}, nil
+}
+
+func (h *Handler) delete(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	env, err := h.env(r)
+	if err != nil {
+		return nil, err
+	}
+	h.mu.Lock()
+	delete(h.envs, mux.Vars(r)["id"])
+	h.mu.Unlock()
+
+	env.Lock()
+	defer env.Unlock()
+	if err := env.Close(); err != nil {
+		return nil, err
+	}
+	w.WriteHeader(204)
+	return nil, nil
+}
+
+func (h *Handler) env(r *http.Request) (*lockedEnv, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	env, exists := h.envs[mux.Vars(r)["id"]]
+	if !exists {
+		return nil, errors.New("not found")
+	}
+	env.used = time.Now()
+	return env, nil
+}
+
+// evict closes the environments nobody has used for the idle timeout
+func (h *Handler) evict() {
+	var idle []*lockedEnv
+	h.mu.Lock()
+	for id, env := range h.envs {
+		if time.Since(env.used) > h.idle {
+			idle = append(idle, env)
+			delete(h.envs, id)
+		}
+	}
+	h.mu.Unlock()
+
+	if err := closeEnvs(idle); err != nil {
+		log.Println(err)
+	}
+}
+
+// closeEnvs closes envs once whatever requests they're in the middle of are done, returning the first error
+func closeEnvs(envs []*lockedEnv) error {
+	var first error
+	for _, env := range envs {
+		env.Lock()
+		if err := env.Close(); err != nil && first == nil {
+			first = err
+		}
+		env.Unlock()
+	}
+	return first
+}
+
+// wrap writes what a handler returns as JSON, the same way the game's API does
+func (h *Handler) wrap(f func(w http.ResponseWriter, r *http.Request) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+
+		m, err := f(w, r)
+		if err != nil {
+			log.Println(err)
+			json.NewEncoder(w).Encode(map[string]string{"at": "error", "msg": err.Error()})
+			return
+		}
+		if m != nil {
+			if err := json.NewEncoder(w).Encode(m); err != nil {
+				log.Println(err)
+			}
+		}
+	}
+}
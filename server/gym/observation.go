@@ -0,0 +1,85 @@
+package gym
+
+import "github.com/fanatic/robot-game/server"
+
+// Channels of an observation's tensor, each a grid of 0s and 1s
+const (
+	ChannelBoard = iota // every cell of the board, so a padded or cropped tensor can tell floor from nothing
+	ChannelSpawn        // spawn points, where robots appear each round
+	ChannelSelf         // the agent
+	ChannelEnemy        // robots the agent can see, or every robot with FullBoard
+	ChannelNorth        // robots facing north, the agent included, then east, south and west
+	ChannelEast
+	ChannelSouth
+	ChannelWest
+	Channels // how many there are
+)
+
+// Observation is what the agent sees after a step
+type Observation struct {
+	Tensor []float32    `json:"tensor"` // Shape flattened, index by channel*grid*grid + y*grid + x
+	Shape  [3]int       `json:"shape"`  // channels, height, width
+	Robot  server.Robot `json:"robot"`  // the agent as the HTTP API would return it, dead or alive
+	Round  int          `json:"round"`
+	Tick   int          `json:"tick"`
+}
+
+// At is the value of channel at x, y
+func (o *Observation) At(channel, x, y int) float32 {
+	return o.Tensor[(channel*o.Shape[1]+y)*o.Shape[2]+x]
+}
+
+func (o *Observation) set(channel, x, y int) {
+	if x < 0 || x >= o.Shape[2] || y < 0 || y >= o.Shape[1] {
+		return
+	}
+	o.Tensor[(channel*o.Shape[1]+y)*o.Shape[2]+x] = 1
+}
+
+// observe encodes the board as the agent sees it
+func (e *Env) observe() (*Observation, error) {
+	state, err := e.g.State()
+	if err != nil {
+		return nil, err
+	}
+	r, err := e.g.ResumeRobot(e.agent)
+	if err != nil {
+		return nil, err
+	}
+
+	grid := state.Grid
+	o := &Observation{
+		Tensor: make([]float32, Channels*grid*grid),
+		Shape:  [3]int{Channels, grid, grid},
+		Robot:  *r,
+		Round:  state.Round,
+		Tick:   state.Tick,
+	}
+	for y := 0; y < grid; y++ {
+		for x := 0; x < grid; x++ {
+			o.set(ChannelBoard, x, y)
+		}
+	}
+	for _, l := range state.SpawnPoints {
+		o.set(ChannelSpawn, l.X, l.Y)
+	}
+
+	if !r.Dead {
+		o.set(ChannelSelf, r.X, r.Y)
+		o.set(ChannelNorth+r.Direction, r.X, r.Y)
+	}
+	enemies := r.InRange
+	if e.config.FullBoard {
+		enemies = nil
+		for _, other := range state.Robots {
+			if other.ID != r.ID && !other.Dead {
+				enemies = append(enemies, server.ShortRobot{Name: other.Name, X: other.X, Y: other.Y, Direction: other.Direction})
+			}
+		}
+	}
+	for _, other := range enemies {
+		o.set(ChannelEnemy, other.X, other.Y)
+		o.set(ChannelNorth+other.Direction, other.X, other.Y)
+	}
+	return o, nil
+}
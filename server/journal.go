@@ -0,0 +1,282 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/fanatic/robot-game/server/wire"
+)
+
+// snapshotInterval is how often the world is written back to the store in full
+const snapshotInterval = 30 * time.Second
+
+// journalRetry is how often writing the journal is tried again after the store failed
+const journalRetry = time.Second
+
+// historyRounds is how many finished rounds the journal is kept for unless WithHistory says otherwise
+const historyRounds = 1000
+
+// Event actions
+const (
+	ActionJoin       = wire.ActionJoin
+	ActionLeave      = wire.ActionLeave
+	ActionMove       = wire.ActionMove
+	ActionTurn       = wire.ActionTurn
+	ActionAttack     = wire.ActionAttack
+	ActionDeath      = wire.ActionDeath
+	ActionRound      = wire.ActionRound
+	ActionSeed       = wire.ActionSeed
+	ActionImport     = wire.ActionImport
+	ActionMap        = wire.ActionMap
+	ActionPopulation = wire.ActionPopulation
+)
+
+// Event is something that happened in the game along with the records it changed
+type Event = wire.Event
+
+// journal holds the events the world has moved on with but the store hasn't written yet. Actions only wait for the
+// world's lock, the store catches up behind them.
+type journal struct {
+	writing sync.Mutex // held while writing, so events reach the store in order
+
+	mu      sync.Mutex
+	pending []Event
+	err     error         // why the store last failed, until it catches up again
+	wake    chan struct{} // nudges journalLoop, nil for games without loops
+}
+
+// apply applies an event to the world and queues it to be journaled. The caller must hold the world's lock.
+func (g *Game) apply(ev *Event) error {
+	j := &g.journal
+	j.mu.Lock()
+	err := j.err
+	j.mu.Unlock()
+	if err != nil {
+		// Don't let the world run away from what could be recovered
+		return fmt.Errorf("the journal can't be written: %v", err)
+	}
+
+	ev.ID = g.world.eventID + 1
+	ev.Round = g.world.state.Round
+	ev.Tick = g.world.state.Tick + 1
+	ev.Time = g.clock.Now()
+	if ev.Result == "" {
+		ev.Result = "ok"
+	}
+	g.world.apply(ev)
+
+	j.mu.Lock()
+	j.pending = append(j.pending, *ev)
+	j.mu.Unlock()
+	select {
+	case j.wake <- struct{}{}:
+	default:
+	}
+	g.notify()
+	return nil
+}
+
+// changes returns a channel that's closed the next time an event is applied, for streams to wait on
+func (g *Game) changes() <-chan struct{} {
+	g.watch.Lock()
+	defer g.watch.Unlock()
+
+	if g.changed == nil {
+		g.changed = make(chan struct{})
+	}
+	return g.changed
+}
+
+// notify wakes everyone waiting on changes
+func (g *Game) notify() {
+	g.watch.Lock()
+	defer g.watch.Unlock()
+
+	if g.changed != nil {
+		close(g.changed)
+		g.changed = nil
+	}
+}
+
+// flush writes every pending event to the store, oldest first
+func (g *Game) flush() error {
+	j := &g.journal
+	j.writing.Lock()
+	defer j.writing.Unlock()
+
+	events := j.take()
+	for i := range events {
+		if err := g.store.AppendEvent(&events[i]); err != nil {
+			// Put back what wasn't written, ahead of anything applied since
+			j.mu.Lock()
+			j.pending = append(events[i:], j.pending...)
+			j.err = err
+			j.mu.Unlock()
+			return err
+		}
+	}
+
+	j.mu.Lock()
+	j.err = nil
+	j.mu.Unlock()
+	return nil
+}
+
+// take empties the pending events and returns them
+func (j *journal) take() []Event {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	events := j.pending
+	j.pending = nil
+	return events
+}
+
+// journalLoop writes events to the store as they are applied, retrying while the store fails
+func (g *Game) journalLoop() {
+	defer g.loops.Done()
+
+	retry := time.NewTicker(journalRetry)
+	defer retry.Stop()
+
+	for {
+		select {
+		case <-g.journal.wake:
+		case <-retry.C:
+			g.journal.mu.Lock()
+			failing := g.journal.err != nil
+			g.journal.mu.Unlock()
+			if !failing {
+				continue
+			}
+		case <-g.done:
+			return
+		}
+
+		if err := g.flush(); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// EventFilter narrows down Events. Empty fields match everything.
+type EventFilter = wire.EventFilter
+
+// Events returns up to limit public events after since that match filter, along with where the next page starts.
+// Only the rounds the game keeps history for are still in the journal.
+func (g *Game) Events(filter EventFilter, since, limit int) ([]Event, int, error) {
+	if err := g.flush(); err != nil {
+		return nil, since, err
+	}
+
+	matched := []Event{}
+	for len(matched) < limit {
+		page, err := g.store.Events(since, limit)
+		if err != nil {
+			return nil, since, err
+		}
+		for i := range page {
+			since = page[i].ID
+			if filter.Match(&page[i]) {
+				matched = append(matched, page[i].Public())
+				if len(matched) == limit {
+					break
+				}
+			}
+		}
+		if len(page) < limit {
+			break
+		}
+	}
+	return matched, since, nil
+}
+
+// recover rebuilds the world from the last snapshot plus the journal since
+func (g *Game) recover() error {
+	state, robots, eventID, err := g.store.LoadSnapshot()
+	if err != nil {
+		return err
+	}
+
+	g.world = newWorld(*state, robots)
+	g.world.eventID = eventID
+
+	events, err := g.store.Events(eventID, 0)
+	if err != nil {
+		return err
+	}
+	for i := range events {
+		g.world.apply(&events[i])
+	}
+	if len(events) > 0 {
+		log.Printf("journal at=recover events=%d\n", len(events))
+	}
+	return nil
+}
+
+// snapshot writes the whole world to the store so recovery only has to replay the journal from here on, then
+// compacts the journal
+func (g *Game) snapshot() error {
+	if err := g.flush(); err != nil {
+		return err
+	}
+
+	w := g.world
+	w.mu.RLock()
+	state := w.state
+	robots := w.copyRobots()
+	eventID := w.eventID
+	w.mu.RUnlock()
+
+	if err := g.store.SaveSnapshot(&state, robots, eventID); err != nil {
+		return err
+	}
+	return g.compact(eventID)
+}
+
+// compact drops the journal of rounds older than the game keeps history for. Their replays go with it but the
+// rounds stay on record. Nothing the snapshot at eventID doesn't include is ever dropped.
+func (g *Game) compact(eventID int) error {
+	if g.history == 0 {
+		return nil
+	}
+	rounds, err := g.store.Rounds()
+	if err != nil {
+		return err
+	}
+	if len(rounds) <= g.history {
+		return nil
+	}
+
+	// The event that ended the round before the oldest one kept sets that round up, so it stays
+	through := rounds[len(rounds)-g.history-1].EventID - 1
+	if through <= g.compacted || through > eventID {
+		// Rounds recorded before their events were, or nothing new to drop
+		return nil
+	}
+	if err := g.store.DropEvents(through); err != nil {
+		return err
+	}
+	g.compacted = through
+	return nil
+}
+
+func (g *Game) snapshotLoop() {
+	defer g.loops.Done()
+
+	ticker := time.NewTicker(snapshotInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := g.snapshot(); err != nil {
+				log.Println(err)
+			}
+		case <-g.done:
+			return
+		}
+	}
+}
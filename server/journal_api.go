@@ -0,0 +1,40 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+const maxEventsLimit = 1000
+
+func getEvents(g *Game, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	query := r.URL.Query()
+
+	since, err := intParam(query.Get("since"), 0)
+	if err != nil {
+		return nil, fmt.Errorf("since must be an event id")
+	}
+	limit, err := intParam(query.Get("limit"), 100)
+	if err != nil || limit < 1 || limit > maxEventsLimit {
+		return nil, fmt.Errorf("limit must be between 1 and %d", maxEventsLimit)
+	}
+
+	events, next, err := g.Events(EventFilter{Action: query.Get("type"), Robot: query.Get("robot")}, since, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return struct {
+		Events []Event `json:"events"`
+		Next   int     `json:"next"` // pass as since for the next page
+	}{events, next}, nil
+}
+
+// intParam parses an optional integer query parameter
+func intParam(value string, fallback int) (int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(value)
+}
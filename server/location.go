@@ -1,39 +1,141 @@
 package server
 
-import "math/rand"
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/fanatic/robot-game/server/wire"
+)
 
 // Directional names for robot direction
 const (
-	North = iota
-	East
-	South
-	West
+	North = wire.North
+	East  = wire.East
+	South = wire.South
+	West  = wire.West
 )
 
 // Location is a coordinate on the grid
-type Location struct {
-	X int
-	Y int
-}
+type Location = wire.Location
 
 func adjacentGridLocations(x, y int) map[int]Location {
 	return map[int]Location{
-		South: Location{x, y + 1},
-		East:  Location{x + 1, y},
-		North: Location{x, y - 1},
-		West:  Location{x - 1, y},
+		South: Location{X: x, Y: y + 1},
+		East:  Location{X: x + 1, Y: y},
+		North: Location{X: x, Y: y - 1},
+		West:  Location{X: x - 1, Y: y},
+	}
+}
+
+func distance(a, b Location) int {
+	return abs(a.X-b.X) + abs(a.Y-b.Y)
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
 	}
+	return i
 }
 
-func (s *State) randomFreeLocation() (x int, y int, direction int) {
-	for i := 0; i < s.Grid*s.Grid; i++ {
-		x = rand.Intn(s.Grid)
-		y = rand.Intn(s.Grid)
-		direction = rand.Intn(4)
+// spawnLocation picks where a robot should (re)enter the grid. Candidates are the map's spawn points, or every
+// cell when the map has none, and the free candidate furthest from any living robot wins. Ties are broken randomly.
+func (w *World) spawnLocation(rng *rand.Rand) (x int, y int, direction int, err error) {
+	if len(w.state.SpawnPoints) == 0 {
+		return w.furthestCell(rng, w.cellList())
+	}
+	return w.furthestCell(rng, w.state.SpawnPoints)
+}
 
-		if r := s.locateRobot(x, y); r == nil || r.Dead {
-			return
+// cellList returns every cell on the grid
+func (w *World) cellList() []Location {
+	grid := w.state.Grid
+	cells := make([]Location, 0, grid*grid)
+	for x := 0; x < grid; x++ {
+		for y := 0; y < grid; y++ {
+			cells = append(cells, Location{X: x, Y: y})
+		}
+	}
+	return cells
+}
+
+// furthestCell picks the free candidate furthest from any living robot, breaking ties randomly
+func (w *World) furthestCell(rng *rand.Rand, candidates []Location) (x int, y int, direction int, err error) {
+	grid := w.state.Grid
+	best := []Location{}
+	bestDistance := -1
+	for _, l := range candidates {
+		if !w.onGrid(l) || w.robotAt(l.X, l.Y) != nil {
+			continue
+		}
+
+		// With nobody else on the grid every free cell is as good as another
+		d := grid * 2
+		for occupied := range w.cells {
+			if od := distance(l, occupied); od < d {
+				d = od
+			}
+		}
+
+		if d > bestDistance {
+			best = best[:0]
+			bestDistance = d
+		}
+		if d == bestDistance {
+			best = append(best, l)
 		}
 	}
-	return
+
+	if len(best) == 0 {
+		return 0, 0, 0, wire.ErrGridFull
+	}
+
+	l := best[rng.Intn(len(best))]
+	return l.X, l.Y, rng.Intn(4), nil
+}
+
+// validateSpawnPoints checks a map's spawn points are all on the grid, with none given twice
+func validateSpawnPoints(points []Location) error {
+	seen := map[Location]bool{}
+	for _, l := range points {
+		if l.X < 0 || l.X >= gridSize || l.Y < 0 || l.Y >= gridSize {
+			return fmt.Errorf("spawn point %d,%d is off the grid", l.X, l.Y)
+		}
+		if seen[l] {
+			return fmt.Errorf("spawn point %d,%d is given twice", l.X, l.Y)
+		}
+		seen[l] = true
+	}
+	return nil
+}
+
+// SpawnPoints returns where robots (re)enter the grid, none meaning anywhere
+func (g *Game) SpawnPoints() []Location {
+	w := g.world
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return append([]Location{}, w.state.SpawnPoints...)
+}
+
+// SetSpawnPoints changes where robots (re)enter the grid from the next spawn on, none meaning anywhere. Robots
+// already on the grid stay where they are, and any there aren't enough points for when the round ends come back
+// wherever is furthest from everyone. Joining always needs a free point.
+func (g *Game) SetSpawnPoints(points []Location) error {
+	if err := validateSpawnPoints(points); err != nil {
+		return err
+	}
+
+	w := g.world
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return g.setSpawnPoints(points)
+}
+
+// setSpawnPoints journals the map changing. The caller must hold the world's lock.
+func (g *Game) setSpawnPoints(points []Location) error {
+	s := g.world.state
+	s.SpawnPoints = points
+	return g.apply(&Event{Action: ActionMap, State: &s})
 }
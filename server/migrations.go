@@ -0,0 +1,81 @@
+package server
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/asdine/storm"
+)
+
+// Migrator is implemented by stores with a schema that needs upgrading before the game can load from them
+type Migrator interface {
+	Migrate() error
+}
+
+// migration moves a storm db from the previous schema version to the next. Append new ones to the end of
+// migrations, never reorder or edit one that has shipped: its position is its version.
+type migration struct {
+	description string
+	up          func(tx storm.Node) error
+}
+
+var migrations = []migration{
+	{"drop robots copied into the saved state", func(tx storm.Node) error {
+		// Rounds used to save the whole State, robots and all, even though only the round number was read back
+		var states []State
+		if err := tx.All(&states); err != nil {
+			if err == storm.ErrNotFound {
+				return nil
+			}
+			return err
+		}
+		if len(states) != 1 {
+			return nil
+		}
+		s := State{ID: 1, Round: states[0].Round, Seed: states[0].Seed, SpawnPoints: states[0].SpawnPoints}
+		return tx.Save(&s)
+	}},
+}
+
+// schemaVersion reads the version a db was last migrated to. Dbs from before migrations existed are version 0.
+func schemaVersion(node storm.Node) (int, error) {
+	var version int
+	if err := node.Get("meta", "schema_version", &version); err != nil && err != storm.ErrNotFound {
+		return 0, err
+	}
+	return version, nil
+}
+
+// Migrate runs, in order, each migration the db hasn't had yet. Each runs in its own transaction together with
+// bumping the recorded version, so a failure leaves the db at the last version that fully applied.
+func (st *StormStore) Migrate() error {
+	version, err := schemaVersion(st.db)
+	if err != nil {
+		return err
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("db schema version %d is newer than this server knows about (%d)", version, len(migrations))
+	}
+
+	for ; version < len(migrations); version++ {
+		m := migrations[version]
+		log.Printf("migrate at=start version=%d description=%q\n", version+1, m.description)
+
+		tx, err := st.db.Begin(true)
+		if err != nil {
+			return err
+		}
+		if err := m.up(tx); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d (%s): %v", version+1, m.description, err)
+		}
+		if err := tx.Set("meta", "schema_version", version+1); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
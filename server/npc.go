@@ -0,0 +1,283 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/fanatic/robot-game/server/wire"
+	"github.com/google/uuid"
+)
+
+// How hard the robots the server plays are to beat
+const (
+	NPCIdle     = "idle"     // stands still, a target to practice on
+	NPCWanderer = "wanderer" // walks about at random, attacking whatever it bumps into
+	NPCHunter   = "hunter"   // heads for the nearest robot and attacks it
+)
+
+// npcNames are tried in order after the first letter of an NPC's difficulty, "H1", "H2" and so on. Players' names
+// are letters only, so these are never taken by anyone else.
+const npcNames = "123456789"
+
+// Population is how many robots the server keeps on the grid, making up the numbers with NPCs
+type Population = wire.Population
+
+func validatePopulation(p Population) error {
+	if p.Minimum < 0 || p.Minimum > len(npcNames) {
+		return fmt.Errorf("minimum population must be between 0 and %d", len(npcNames))
+	}
+	if p.Minimum > 0 {
+		return validateDifficulty(p.Difficulty)
+	}
+	return nil
+}
+
+func validateDifficulty(difficulty string) error {
+	switch difficulty {
+	case NPCIdle, NPCWanderer, NPCHunter:
+		return nil
+	}
+	return fmt.Errorf("difficulty must be %s, %s or %s", NPCIdle, NPCWanderer, NPCHunter)
+}
+
+// AddNPC puts a robot on the grid that the server plays
+func (g *Game) AddNPC(difficulty string) (*Robot, error) {
+	if err := validateDifficulty(difficulty); err != nil {
+		return nil, err
+	}
+
+	w := g.world
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	r, err := g.addNPC(difficulty)
+	if err != nil {
+		return nil, err
+	}
+	g.startNPCs()
+	return r, nil
+}
+
+// addNPC is AddNPC for callers already holding the world's lock
+func (g *Game) addNPC(difficulty string) (*Robot, error) {
+	letter := strings.ToUpper(difficulty[:1])
+	for _, c := range npcNames {
+		name := letter + string(c)
+		if g.world.named(name) {
+			continue
+		}
+		id, _ := uuid.NewRandom()
+		return g.join(name, id.String(), difficulty)
+	}
+	return nil, fmt.Errorf("no more %s npcs - every name is taken", difficulty)
+}
+
+// startNPCs starts the loop playing NPCs once there are some, or a population to make up with them. The caller must
+// hold the world's lock.
+func (g *Game) startNPCs() {
+	if g.npcs || g.noLoops || !g.wantNPCs() {
+		return
+	}
+	select {
+	case <-g.done:
+		// Closing, and the lock keeps Close from waiting for the loops until this is settled
+		return
+	default:
+	}
+	g.npcs = true
+	g.loops.Add(1)
+	go g.npcLoop()
+}
+
+func (g *Game) wantNPCs() bool {
+	w := g.world
+	if p := w.state.Population; p != nil && p.Minimum > 0 {
+		return true
+	}
+	for _, r := range w.robots {
+		if r.NPC != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveNPC takes a robot the server plays off the grid
+func (g *Game) RemoveNPC(id string) error {
+	w := g.world
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	r, err := w.robot(id)
+	if err != nil {
+		return err
+	}
+	if r.NPC == "" {
+		return fmt.Errorf("that robot is a player")
+	}
+	return g.leave(id)
+}
+
+// Population returns how many robots the server keeps on the grid
+func (g *Game) Population() Population {
+	w := g.world
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.state.Population == nil {
+		return Population{}
+	}
+	return *w.state.Population
+}
+
+// SetPopulation changes how many robots the server keeps on the grid. NPCs are added or removed over the next few
+// ticks, players are never removed.
+func (g *Game) SetPopulation(p Population) error {
+	if err := validatePopulation(p); err != nil {
+		return err
+	}
+
+	w := g.world
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := g.setPopulation(p); err != nil {
+		return err
+	}
+	g.startNPCs()
+	return nil
+}
+
+// setPopulation journals the population so it outlives the process. The caller must hold the world's lock.
+func (g *Game) setPopulation(p Population) error {
+	s := g.world.state
+	s.Population = &p
+	return g.apply(&Event{Action: ActionPopulation, State: &s})
+}
+
+// npcLoop plays every NPC once each actionDelay of the game's clock, the same pace a player's requests are held to
+func (g *Game) npcLoop() {
+	defer g.loops.Done()
+
+	for {
+		select {
+		case <-g.clock.After(actionDelay):
+			g.npcTurn()
+		case <-g.done:
+			return
+		}
+	}
+}
+
+// npcTurn has every NPC act once
+func (g *Game) npcTurn() {
+	w := g.world
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	g.keepPopulation()
+
+	var npcs []string
+	for _, r := range w.robots {
+		if r.NPC != "" {
+			npcs = append(npcs, r.ID)
+		}
+	}
+
+	for _, id := range npcs {
+		r, err := w.living(id)
+		if err != nil {
+			// Dead, or gone with the last robot's attack
+			continue
+		}
+		ev := g.decide(r)
+		if ev == nil {
+			continue
+		}
+		// Misses and bumps are journaled like anyone's, with why as their result
+		g.act(ev)
+	}
+}
+
+// keepPopulation adds or removes an NPC when the grid is away from the minimum population
+func (g *Game) keepPopulation() {
+	w := g.world
+	p := w.state.Population
+	if p == nil || p.Minimum == 0 {
+		return
+	}
+
+	if len(w.robots) < p.Minimum {
+		if _, err := g.addNPC(p.Difficulty); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+
+	if len(w.robots) > p.Minimum {
+		// Make room for players, the dead first
+		var surplus *Robot
+		for _, r := range w.robots {
+			if r.NPC != "" && (surplus == nil || r.Dead && !surplus.Dead) {
+				surplus = r
+			}
+		}
+		if surplus != nil {
+			if err := g.leave(surplus.ID); err != nil {
+				log.Println(err)
+			}
+		}
+	}
+}
+
+// decide picks an NPC's next action, or nil to stand still
+func (g *Game) decide(r Robot) *Event {
+	w := g.world
+	ahead := adjacentGridLocations(r.X, r.Y)[r.Direction]
+	if r.NPC != NPCIdle && w.robotAt(ahead.X, ahead.Y) != nil {
+		return &Event{Action: ActionAttack, RobotID: r.ID}
+	}
+
+	switch r.NPC {
+	case NPCHunter:
+		if target := w.nearest(r); target != nil {
+			return g.chase(r, target)
+		}
+		fallthrough
+	case NPCWanderer:
+		rng := w.rng()
+		if !w.onGrid(ahead) || rng.Intn(4) == 0 {
+			return &Event{Action: ActionTurn, RobotID: r.ID, Left: rng.Intn(2) == 0}
+		}
+		return &Event{Action: ActionMove, RobotID: r.ID}
+	}
+	return nil
+}
+
+// chase moves r towards target, turning to face it once it's next to it
+func (g *Game) chase(r Robot, target *Robot) *Event {
+	dx, dy := target.X-r.X, target.Y-r.Y
+
+	// Keep going while that closes the gap, otherwise turn along the longer way to go
+	want := r.Direction
+	switch {
+	case r.Direction == East && dx > 0, r.Direction == West && dx < 0, r.Direction == South && dy > 0, r.Direction == North && dy < 0:
+	case abs(dx) >= abs(dy) && dx > 0:
+		want = East
+	case abs(dx) >= abs(dy):
+		want = West
+	case dy > 0:
+		want = South
+	default:
+		want = North
+	}
+
+	switch (want - r.Direction + 4) % 4 {
+	case 0:
+		return &Event{Action: ActionMove, RobotID: r.ID}
+	case 3:
+		return &Event{Action: ActionTurn, RobotID: r.ID, Left: true}
+	}
+	return &Event{Action: ActionTurn, RobotID: r.ID}
+}
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func postNPC(g *Game, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	payload := struct {
+		Difficulty string `json:"difficulty"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("invalid payload body {\"difficulty\": \"%s\"}", NPCWanderer)
+	}
+
+	return g.AddNPC(payload.Difficulty)
+}
+
+func deleteNPC(g *Game, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	id := mux.Vars(r)["id"]
+
+	if err := g.RemoveNPC(id); err != nil {
+		return nil, err
+	}
+
+	w.WriteHeader(204)
+	return nil, nil
+}
+
+func getPopulation(g *Game, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	return g.Population(), nil
+}
+
+func putPopulation(g *Game, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	var p Population
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		return nil, fmt.Errorf("invalid payload body {\"minimum\": 4, \"difficulty\": \"%s\"}", NPCWanderer)
+	}
+
+	if err := g.SetPopulation(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
@@ -0,0 +1,5 @@
+// Package pb is the generated gRPC API for the robot game, see robot_game.proto. Other languages can generate their
+// own clients from the same file.
+package pb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative robot_game.proto
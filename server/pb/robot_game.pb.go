@@ -0,0 +1,866 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: robot_game.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Direction int32
+
+const (
+	Direction_NORTH Direction = 0
+	Direction_EAST  Direction = 1
+	Direction_SOUTH Direction = 2
+	Direction_WEST  Direction = 3
+)
+
+// Enum value maps for Direction.
+var (
+	Direction_name = map[int32]string{
+		0: "NORTH",
+		1: "EAST",
+		2: "SOUTH",
+		3: "WEST",
+	}
+	Direction_value = map[string]int32{
+		"NORTH": 0,
+		"EAST":  1,
+		"SOUTH": 2,
+		"WEST":  3,
+	}
+)
+
+func (x Direction) Enum() *Direction {
+	p := new(Direction)
+	*p = x
+	return p
+}
+
+func (x Direction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Direction) Descriptor() protoreflect.EnumDescriptor {
+	return file_robot_game_proto_enumTypes[0].Descriptor()
+}
+
+func (Direction) Type() protoreflect.EnumType {
+	return &file_robot_game_proto_enumTypes[0]
+}
+
+func (x Direction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Direction.Descriptor instead.
+func (Direction) EnumDescriptor() ([]byte, []int) {
+	return file_robot_game_proto_rawDescGZIP(), []int{0}
+}
+
+type Robot struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Name           string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	X              int32                  `protobuf:"varint,4,opt,name=x,proto3" json:"x,omitempty"`
+	Y              int32                  `protobuf:"varint,5,opt,name=y,proto3" json:"y,omitempty"`
+	Color          string                 `protobuf:"bytes,6,opt,name=color,proto3" json:"color,omitempty"`
+	Direction      Direction              `protobuf:"varint,7,opt,name=direction,proto3,enum=robotgame.Direction" json:"direction,omitempty"`
+	Vision         int32                  `protobuf:"varint,8,opt,name=vision,proto3" json:"vision,omitempty"`
+	Score          int32                  `protobuf:"varint,9,opt,name=score,proto3" json:"score,omitempty"`
+	Dead           bool                   `protobuf:"varint,10,opt,name=dead,proto3" json:"dead,omitempty"`
+	ProtectedUntil *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=protected_until,json=protectedUntil,proto3" json:"protected_until,omitempty"`
+	Npc            string                 `protobuf:"bytes,12,opt,name=npc,proto3" json:"npc,omitempty"`
+	RobotsInRange  []*ShortRobot          `protobuf:"bytes,13,rep,name=robots_in_range,json=robotsInRange,proto3" json:"robots_in_range,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Robot) Reset() {
+	*x = Robot{}
+	mi := &file_robot_game_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Robot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Robot) ProtoMessage() {}
+
+func (x *Robot) ProtoReflect() protoreflect.Message {
+	mi := &file_robot_game_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Robot.ProtoReflect.Descriptor instead.
+func (*Robot) Descriptor() ([]byte, []int) {
+	return file_robot_game_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Robot) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Robot) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Robot) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Robot) GetX() int32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *Robot) GetY() int32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+func (x *Robot) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+func (x *Robot) GetDirection() Direction {
+	if x != nil {
+		return x.Direction
+	}
+	return Direction_NORTH
+}
+
+func (x *Robot) GetVision() int32 {
+	if x != nil {
+		return x.Vision
+	}
+	return 0
+}
+
+func (x *Robot) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Robot) GetDead() bool {
+	if x != nil {
+		return x.Dead
+	}
+	return false
+}
+
+func (x *Robot) GetProtectedUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ProtectedUntil
+	}
+	return nil
+}
+
+func (x *Robot) GetNpc() string {
+	if x != nil {
+		return x.Npc
+	}
+	return ""
+}
+
+func (x *Robot) GetRobotsInRange() []*ShortRobot {
+	if x != nil {
+		return x.RobotsInRange
+	}
+	return nil
+}
+
+type ShortRobot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	X             int32                  `protobuf:"varint,2,opt,name=x,proto3" json:"x,omitempty"`
+	Y             int32                  `protobuf:"varint,3,opt,name=y,proto3" json:"y,omitempty"`
+	Direction     Direction              `protobuf:"varint,4,opt,name=direction,proto3,enum=robotgame.Direction" json:"direction,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShortRobot) Reset() {
+	*x = ShortRobot{}
+	mi := &file_robot_game_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShortRobot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortRobot) ProtoMessage() {}
+
+func (x *ShortRobot) ProtoReflect() protoreflect.Message {
+	mi := &file_robot_game_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortRobot.ProtoReflect.Descriptor instead.
+func (*ShortRobot) Descriptor() ([]byte, []int) {
+	return file_robot_game_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ShortRobot) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ShortRobot) GetX() int32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *ShortRobot) GetY() int32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+func (x *ShortRobot) GetDirection() Direction {
+	if x != nil {
+		return x.Direction
+	}
+	return Direction_NORTH
+}
+
+type Location struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	X             int32                  `protobuf:"varint,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y             int32                  `protobuf:"varint,2,opt,name=y,proto3" json:"y,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Location) Reset() {
+	*x = Location{}
+	mi := &file_robot_game_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Location) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Location) ProtoMessage() {}
+
+func (x *Location) ProtoReflect() protoreflect.Message {
+	mi := &file_robot_game_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Location.ProtoReflect.Descriptor instead.
+func (*Location) Descriptor() ([]byte, []int) {
+	return file_robot_game_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Location) GetX() int32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *Location) GetY() int32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+type State struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Round         int32                  `protobuf:"varint,1,opt,name=round,proto3" json:"round,omitempty"`
+	Tick          int32                  `protobuf:"varint,3,opt,name=tick,proto3" json:"tick,omitempty"`
+	SpawnPoints   []*Location            `protobuf:"bytes,4,rep,name=spawn_points,json=spawnPoints,proto3" json:"spawn_points,omitempty"`
+	Grid          int32                  `protobuf:"varint,5,opt,name=grid,proto3" json:"grid,omitempty"`
+	Robots        []*Robot               `protobuf:"bytes,6,rep,name=robots,proto3" json:"robots,omitempty"`
+	Delay         *durationpb.Duration   `protobuf:"bytes,7,opt,name=delay,proto3" json:"delay,omitempty"`
+	RobotLimit    int32                  `protobuf:"varint,8,opt,name=robot_limit,json=robotLimit,proto3" json:"robot_limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *State) Reset() {
+	*x = State{}
+	mi := &file_robot_game_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *State) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*State) ProtoMessage() {}
+
+func (x *State) ProtoReflect() protoreflect.Message {
+	mi := &file_robot_game_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use State.ProtoReflect.Descriptor instead.
+func (*State) Descriptor() ([]byte, []int) {
+	return file_robot_game_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *State) GetRound() int32 {
+	if x != nil {
+		return x.Round
+	}
+	return 0
+}
+
+func (x *State) GetTick() int32 {
+	if x != nil {
+		return x.Tick
+	}
+	return 0
+}
+
+func (x *State) GetSpawnPoints() []*Location {
+	if x != nil {
+		return x.SpawnPoints
+	}
+	return nil
+}
+
+func (x *State) GetGrid() int32 {
+	if x != nil {
+		return x.Grid
+	}
+	return 0
+}
+
+func (x *State) GetRobots() []*Robot {
+	if x != nil {
+		return x.Robots
+	}
+	return nil
+}
+
+func (x *State) GetDelay() *durationpb.Duration {
+	if x != nil {
+		return x.Delay
+	}
+	return nil
+}
+
+func (x *State) GetRobotLimit() int32 {
+	if x != nil {
+		return x.RobotLimit
+	}
+	return 0
+}
+
+type Observation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Round         int32                  `protobuf:"varint,1,opt,name=round,proto3" json:"round,omitempty"`
+	Tick          int32                  `protobuf:"varint,2,opt,name=tick,proto3" json:"tick,omitempty"`
+	Grid          int32                  `protobuf:"varint,3,opt,name=grid,proto3" json:"grid,omitempty"`
+	Robot         *Robot                 `protobuf:"bytes,4,opt,name=robot,proto3" json:"robot,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Observation) Reset() {
+	*x = Observation{}
+	mi := &file_robot_game_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Observation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Observation) ProtoMessage() {}
+
+func (x *Observation) ProtoReflect() protoreflect.Message {
+	mi := &file_robot_game_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Observation.ProtoReflect.Descriptor instead.
+func (*Observation) Descriptor() ([]byte, []int) {
+	return file_robot_game_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Observation) GetRound() int32 {
+	if x != nil {
+		return x.Round
+	}
+	return 0
+}
+
+func (x *Observation) GetTick() int32 {
+	if x != nil {
+		return x.Tick
+	}
+	return 0
+}
+
+func (x *Observation) GetGrid() int32 {
+	if x != nil {
+		return x.Grid
+	}
+	return 0
+}
+
+func (x *Observation) GetRobot() *Robot {
+	if x != nil {
+		return x.Robot
+	}
+	return nil
+}
+
+type JoinRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JoinRequest) Reset() {
+	*x = JoinRequest{}
+	mi := &file_robot_game_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JoinRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinRequest) ProtoMessage() {}
+
+func (x *JoinRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_robot_game_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinRequest.ProtoReflect.Descriptor instead.
+func (*JoinRequest) Descriptor() ([]byte, []int) {
+	return file_robot_game_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *JoinRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type RobotRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RobotRequest) Reset() {
+	*x = RobotRequest{}
+	mi := &file_robot_game_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RobotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RobotRequest) ProtoMessage() {}
+
+func (x *RobotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_robot_game_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RobotRequest.ProtoReflect.Descriptor instead.
+func (*RobotRequest) Descriptor() ([]byte, []int) {
+	return file_robot_game_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RobotRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type TurnRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Left          bool                   `protobuf:"varint,2,opt,name=left,proto3" json:"left,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TurnRequest) Reset() {
+	*x = TurnRequest{}
+	mi := &file_robot_game_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TurnRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TurnRequest) ProtoMessage() {}
+
+func (x *TurnRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_robot_game_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TurnRequest.ProtoReflect.Descriptor instead.
+func (*TurnRequest) Descriptor() ([]byte, []int) {
+	return file_robot_game_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *TurnRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TurnRequest) GetLeft() bool {
+	if x != nil {
+		return x.Left
+	}
+	return false
+}
+
+type StateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StateRequest) Reset() {
+	*x = StateRequest{}
+	mi := &file_robot_game_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StateRequest) ProtoMessage() {}
+
+func (x *StateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_robot_game_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StateRequest.ProtoReflect.Descriptor instead.
+func (*StateRequest) Descriptor() ([]byte, []int) {
+	return file_robot_game_proto_rawDescGZIP(), []int{8}
+}
+
+type LeaveResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaveResponse) Reset() {
+	*x = LeaveResponse{}
+	mi := &file_robot_game_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaveResponse) ProtoMessage() {}
+
+func (x *LeaveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_robot_game_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaveResponse.ProtoReflect.Descriptor instead.
+func (*LeaveResponse) Descriptor() ([]byte, []int) {
+	return file_robot_game_proto_rawDescGZIP(), []int{9}
+}
+
+var File_robot_game_proto protoreflect.FileDescriptor
+
+const file_robot_game_proto_rawDesc = "" +
+	"\n" +
+	"\x10robot_game.proto\x12\trobotgame\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa4\x03\n" +
+	"\x05Robot\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x129\n" +
+	"\n" +
+	"created_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\f\n" +
+	"\x01x\x18\x04 \x01(\x05R\x01x\x12\f\n" +
+	"\x01y\x18\x05 \x01(\x05R\x01y\x12\x14\n" +
+	"\x05color\x18\x06 \x01(\tR\x05color\x122\n" +
+	"\tdirection\x18\a \x01(\x0e2\x14.robotgame.DirectionR\tdirection\x12\x16\n" +
+	"\x06vision\x18\b \x01(\x05R\x06vision\x12\x14\n" +
+	"\x05score\x18\t \x01(\x05R\x05score\x12\x12\n" +
+	"\x04dead\x18\n" +
+	" \x01(\bR\x04dead\x12C\n" +
+	"\x0fprotected_until\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\x0eprotectedUntil\x12\x10\n" +
+	"\x03npc\x18\f \x01(\tR\x03npc\x12=\n" +
+	"\x0frobots_in_range\x18\r \x03(\v2\x15.robotgame.ShortRobotR\rrobotsInRange\"p\n" +
+	"\n" +
+	"ShortRobot\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\f\n" +
+	"\x01x\x18\x02 \x01(\x05R\x01x\x12\f\n" +
+	"\x01y\x18\x03 \x01(\x05R\x01y\x122\n" +
+	"\tdirection\x18\x04 \x01(\x0e2\x14.robotgame.DirectionR\tdirection\"&\n" +
+	"\bLocation\x12\f\n" +
+	"\x01x\x18\x01 \x01(\x05R\x01x\x12\f\n" +
+	"\x01y\x18\x02 \x01(\x05R\x01y\"\x85\x02\n" +
+	"\x05State\x12\x14\n" +
+	"\x05round\x18\x01 \x01(\x05R\x05round\x12\x12\n" +
+	"\x04tick\x18\x03 \x01(\x05R\x04tick\x126\n" +
+	"\fspawn_points\x18\x04 \x03(\v2\x13.robotgame.LocationR\vspawnPoints\x12\x12\n" +
+	"\x04grid\x18\x05 \x01(\x05R\x04grid\x12(\n" +
+	"\x06robots\x18\x06 \x03(\v2\x10.robotgame.RobotR\x06robots\x12/\n" +
+	"\x05delay\x18\a \x01(\v2\x19.google.protobuf.DurationR\x05delay\x12\x1f\n" +
+	"\vrobot_limit\x18\b \x01(\x05R\n" +
+	"robotLimitJ\x04\b\x02\x10\x03R\x04seed\"s\n" +
+	"\vObservation\x12\x14\n" +
+	"\x05round\x18\x01 \x01(\x05R\x05round\x12\x12\n" +
+	"\x04tick\x18\x02 \x01(\x05R\x04tick\x12\x12\n" +
+	"\x04grid\x18\x03 \x01(\x05R\x04grid\x12&\n" +
+	"\x05robot\x18\x04 \x01(\v2\x10.robotgame.RobotR\x05robot\"!\n" +
+	"\vJoinRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\x1e\n" +
+	"\fRobotRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"1\n" +
+	"\vTurnRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04left\x18\x02 \x01(\bR\x04left\"\x0e\n" +
+	"\fStateRequest\"\x0f\n" +
+	"\rLeaveResponse*5\n" +
+	"\tDirection\x12\t\n" +
+	"\x05NORTH\x10\x00\x12\b\n" +
+	"\x04EAST\x10\x01\x12\t\n" +
+	"\x05SOUTH\x10\x02\x12\b\n" +
+	"\x04WEST\x10\x032\xf4\x03\n" +
+	"\tRobotGame\x120\n" +
+	"\x04Join\x12\x16.robotgame.JoinRequest\x1a\x10.robotgame.Robot\x123\n" +
+	"\x06Resume\x12\x17.robotgame.RobotRequest\x1a\x10.robotgame.Robot\x125\n" +
+	"\bGetRobot\x12\x17.robotgame.RobotRequest\x1a\x10.robotgame.Robot\x12:\n" +
+	"\x05Leave\x12\x17.robotgame.RobotRequest\x1a\x18.robotgame.LeaveResponse\x121\n" +
+	"\x04Move\x12\x17.robotgame.RobotRequest\x1a\x10.robotgame.Robot\x120\n" +
+	"\x04Turn\x12\x16.robotgame.TurnRequest\x1a\x10.robotgame.Robot\x123\n" +
+	"\x06Attack\x12\x17.robotgame.RobotRequest\x1a\x10.robotgame.Robot\x125\n" +
+	"\bGetState\x12\x17.robotgame.StateRequest\x1a\x10.robotgame.State\x12<\n" +
+	"\aObserve\x12\x17.robotgame.RobotRequest\x1a\x16.robotgame.Observation0\x01B)Z'github.com/fanatic/robot-game/server/pbb\x06proto3"
+
+var (
+	file_robot_game_proto_rawDescOnce sync.Once
+	file_robot_game_proto_rawDescData []byte
+)
+
+func file_robot_game_proto_rawDescGZIP() []byte {
+	file_robot_game_proto_rawDescOnce.Do(func() {
+		file_robot_game_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_robot_game_proto_rawDesc), len(file_robot_game_proto_rawDesc)))
+	})
+	return file_robot_game_proto_rawDescData
+}
+
+var file_robot_game_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_robot_game_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_robot_game_proto_goTypes = []any{
+	(Direction)(0),                // 0: robotgame.Direction
+	(*Robot)(nil),                 // 1: robotgame.Robot
+	(*ShortRobot)(nil),            // 2: robotgame.ShortRobot
+	(*Location)(nil),              // 3: robotgame.Location
+	(*State)(nil),                 // 4: robotgame.State
+	(*Observation)(nil),           // 5: robotgame.Observation
+	(*JoinRequest)(nil),           // 6: robotgame.JoinRequest
+	(*RobotRequest)(nil),          // 7: robotgame.RobotRequest
+	(*TurnRequest)(nil),           // 8: robotgame.TurnRequest
+	(*StateRequest)(nil),          // 9: robotgame.StateRequest
+	(*LeaveResponse)(nil),         // 10: robotgame.LeaveResponse
+	(*timestamppb.Timestamp)(nil), // 11: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),   // 12: google.protobuf.Duration
+}
+var file_robot_game_proto_depIdxs = []int32{
+	11, // 0: robotgame.Robot.created_at:type_name -> google.protobuf.Timestamp
+	0,  // 1: robotgame.Robot.direction:type_name -> robotgame.Direction
+	11, // 2: robotgame.Robot.protected_until:type_name -> google.protobuf.Timestamp
+	2,  // 3: robotgame.Robot.robots_in_range:type_name -> robotgame.ShortRobot
+	0,  // 4: robotgame.ShortRobot.direction:type_name -> robotgame.Direction
+	3,  // 5: robotgame.State.spawn_points:type_name -> robotgame.Location
+	1,  // 6: robotgame.State.robots:type_name -> robotgame.Robot
+	12, // 7: robotgame.State.delay:type_name -> google.protobuf.Duration
+	1,  // 8: robotgame.Observation.robot:type_name -> robotgame.Robot
+	6,  // 9: robotgame.RobotGame.Join:input_type -> robotgame.JoinRequest
+	7,  // 10: robotgame.RobotGame.Resume:input_type -> robotgame.RobotRequest
+	7,  // 11: robotgame.RobotGame.GetRobot:input_type -> robotgame.RobotRequest
+	7,  // 12: robotgame.RobotGame.Leave:input_type -> robotgame.RobotRequest
+	7,  // 13: robotgame.RobotGame.Move:input_type -> robotgame.RobotRequest
+	8,  // 14: robotgame.RobotGame.Turn:input_type -> robotgame.TurnRequest
+	7,  // 15: robotgame.RobotGame.Attack:input_type -> robotgame.RobotRequest
+	9,  // 16: robotgame.RobotGame.GetState:input_type -> robotgame.StateRequest
+	7,  // 17: robotgame.RobotGame.Observe:input_type -> robotgame.RobotRequest
+	1,  // 18: robotgame.RobotGame.Join:output_type -> robotgame.Robot
+	1,  // 19: robotgame.RobotGame.Resume:output_type -> robotgame.Robot
+	1,  // 20: robotgame.RobotGame.GetRobot:output_type -> robotgame.Robot
+	10, // 21: robotgame.RobotGame.Leave:output_type -> robotgame.LeaveResponse
+	1,  // 22: robotgame.RobotGame.Move:output_type -> robotgame.Robot
+	1,  // 23: robotgame.RobotGame.Turn:output_type -> robotgame.Robot
+	1,  // 24: robotgame.RobotGame.Attack:output_type -> robotgame.Robot
+	4,  // 25: robotgame.RobotGame.GetState:output_type -> robotgame.State
+	5,  // 26: robotgame.RobotGame.Observe:output_type -> robotgame.Observation
+	18, // [18:27] is the sub-list for method output_type
+	9,  // [9:18] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_robot_game_proto_init() }
+func file_robot_game_proto_init() {
+	if File_robot_game_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_robot_game_proto_rawDesc), len(file_robot_game_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_robot_game_proto_goTypes,
+		DependencyIndexes: file_robot_game_proto_depIdxs,
+		EnumInfos:         file_robot_game_proto_enumTypes,
+		MessageInfos:      file_robot_game_proto_msgTypes,
+	}.Build()
+	File_robot_game_proto = out.File
+	file_robot_game_proto_goTypes = nil
+	file_robot_game_proto_depIdxs = nil
+}
@@ -0,0 +1,443 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: robot_game.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	RobotGame_Join_FullMethodName     = "/robotgame.RobotGame/Join"
+	RobotGame_Resume_FullMethodName   = "/robotgame.RobotGame/Resume"
+	RobotGame_GetRobot_FullMethodName = "/robotgame.RobotGame/GetRobot"
+	RobotGame_Leave_FullMethodName    = "/robotgame.RobotGame/Leave"
+	RobotGame_Move_FullMethodName     = "/robotgame.RobotGame/Move"
+	RobotGame_Turn_FullMethodName     = "/robotgame.RobotGame/Turn"
+	RobotGame_Attack_FullMethodName   = "/robotgame.RobotGame/Attack"
+	RobotGame_GetState_FullMethodName = "/robotgame.RobotGame/GetState"
+	RobotGame_Observe_FullMethodName  = "/robotgame.RobotGame/Observe"
+)
+
+// RobotGameClient is the client API for RobotGame service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// The robot game over gRPC. It mirrors the HTTP API: a robot's ID is its secret, so every action takes it, and
+// refusals the HTTP API answers with {"at":"error"} come back as FailedPrecondition.
+type RobotGameClient interface {
+	// Join adds a new robot, keep its ID
+	Join(ctx context.Context, in *JoinRequest, opts ...grpc.CallOption) (*Robot, error)
+	// Resume picks up a robot already joined, dead or alive
+	Resume(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*Robot, error)
+	GetRobot(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*Robot, error)
+	Leave(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*LeaveResponse, error)
+	Move(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*Robot, error)
+	Turn(ctx context.Context, in *TurnRequest, opts ...grpc.CallOption) (*Robot, error)
+	Attack(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*Robot, error)
+	// GetState is the whole board, without robot IDs
+	GetState(ctx context.Context, in *StateRequest, opts ...grpc.CallOption) (*State, error)
+	// Observe sends the robot as it sees the board whenever the tick moves on, until it leaves or the call is cancelled
+	Observe(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Observation], error)
+}
+
+type robotGameClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRobotGameClient(cc grpc.ClientConnInterface) RobotGameClient {
+	return &robotGameClient{cc}
+}
+
+func (c *robotGameClient) Join(ctx context.Context, in *JoinRequest, opts ...grpc.CallOption) (*Robot, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Robot)
+	err := c.cc.Invoke(ctx, RobotGame_Join_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotGameClient) Resume(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*Robot, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Robot)
+	err := c.cc.Invoke(ctx, RobotGame_Resume_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotGameClient) GetRobot(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*Robot, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Robot)
+	err := c.cc.Invoke(ctx, RobotGame_GetRobot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotGameClient) Leave(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*LeaveResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LeaveResponse)
+	err := c.cc.Invoke(ctx, RobotGame_Leave_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotGameClient) Move(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*Robot, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Robot)
+	err := c.cc.Invoke(ctx, RobotGame_Move_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotGameClient) Turn(ctx context.Context, in *TurnRequest, opts ...grpc.CallOption) (*Robot, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Robot)
+	err := c.cc.Invoke(ctx, RobotGame_Turn_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotGameClient) Attack(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*Robot, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Robot)
+	err := c.cc.Invoke(ctx, RobotGame_Attack_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotGameClient) GetState(ctx context.Context, in *StateRequest, opts ...grpc.CallOption) (*State, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(State)
+	err := c.cc.Invoke(ctx, RobotGame_GetState_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotGameClient) Observe(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Observation], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &RobotGame_ServiceDesc.Streams[0], RobotGame_Observe_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[RobotRequest, Observation]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RobotGame_ObserveClient = grpc.ServerStreamingClient[Observation]
+
+// RobotGameServer is the server API for RobotGame service.
+// All implementations must embed UnimplementedRobotGameServer
+// for forward compatibility.
+//
+// The robot game over gRPC. It mirrors the HTTP API: a robot's ID is its secret, so every action takes it, and
+// refusals the HTTP API answers with {"at":"error"} come back as FailedPrecondition.
+type RobotGameServer interface {
+	// Join adds a new robot, keep its ID
+	Join(context.Context, *JoinRequest) (*Robot, error)
+	// Resume picks up a robot already joined, dead or alive
+	Resume(context.Context, *RobotRequest) (*Robot, error)
+	GetRobot(context.Context, *RobotRequest) (*Robot, error)
+	Leave(context.Context, *RobotRequest) (*LeaveResponse, error)
+	Move(context.Context, *RobotRequest) (*Robot, error)
+	Turn(context.Context, *TurnRequest) (*Robot, error)
+	Attack(context.Context, *RobotRequest) (*Robot, error)
+	// GetState is the whole board, without robot IDs
+	GetState(context.Context, *StateRequest) (*State, error)
+	// Observe sends the robot as it sees the board whenever the tick moves on, until it leaves or the call is cancelled
+	Observe(*RobotRequest, grpc.ServerStreamingServer[Observation]) error
+	mustEmbedUnimplementedRobotGameServer()
+}
+
+// UnimplementedRobotGameServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRobotGameServer struct{}
+
+func (UnimplementedRobotGameServer) Join(context.Context, *JoinRequest) (*Robot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Join not implemented")
+}
+func (UnimplementedRobotGameServer) Resume(context.Context, *RobotRequest) (*Robot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Resume not implemented")
+}
+func (UnimplementedRobotGameServer) GetRobot(context.Context, *RobotRequest) (*Robot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRobot not implemented")
+}
+func (UnimplementedRobotGameServer) Leave(context.Context, *RobotRequest) (*LeaveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Leave not implemented")
+}
+func (UnimplementedRobotGameServer) Move(context.Context, *RobotRequest) (*Robot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Move not implemented")
+}
+func (UnimplementedRobotGameServer) Turn(context.Context, *TurnRequest) (*Robot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Turn not implemented")
+}
+func (UnimplementedRobotGameServer) Attack(context.Context, *RobotRequest) (*Robot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Attack not implemented")
+}
+func (UnimplementedRobotGameServer) GetState(context.Context, *StateRequest) (*State, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetState not implemented")
+}
+func (UnimplementedRobotGameServer) Observe(*RobotRequest, grpc.ServerStreamingServer[Observation]) error {
+	return status.Errorf(codes.Unimplemented, "method Observe not implemented")
+}
+func (UnimplementedRobotGameServer) mustEmbedUnimplementedRobotGameServer() {}
+func (UnimplementedRobotGameServer) testEmbeddedByValue()                   {}
+
+// UnsafeRobotGameServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RobotGameServer will
+// result in compilation errors.
+type UnsafeRobotGameServer interface {
+	mustEmbedUnimplementedRobotGameServer()
+}
+
+func RegisterRobotGameServer(s grpc.ServiceRegistrar, srv RobotGameServer) {
+	// If the following call pancis, it indicates UnimplementedRobotGameServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&RobotGame_ServiceDesc, srv)
+}
+
+func _RobotGame_Join_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JoinRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotGameServer).Join(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RobotGame_Join_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotGameServer).Join(ctx, req.(*JoinRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotGame_Resume_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RobotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotGameServer).Resume(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RobotGame_Resume_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotGameServer).Resume(ctx, req.(*RobotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotGame_GetRobot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RobotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotGameServer).GetRobot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RobotGame_GetRobot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotGameServer).GetRobot(ctx, req.(*RobotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotGame_Leave_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RobotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotGameServer).Leave(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RobotGame_Leave_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotGameServer).Leave(ctx, req.(*RobotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotGame_Move_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RobotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotGameServer).Move(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RobotGame_Move_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotGameServer).Move(ctx, req.(*RobotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotGame_Turn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TurnRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotGameServer).Turn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RobotGame_Turn_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotGameServer).Turn(ctx, req.(*TurnRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotGame_Attack_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RobotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotGameServer).Attack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RobotGame_Attack_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotGameServer).Attack(ctx, req.(*RobotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotGame_GetState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotGameServer).GetState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RobotGame_GetState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotGameServer).GetState(ctx, req.(*StateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotGame_Observe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RobotRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RobotGameServer).Observe(m, &grpc.GenericServerStream[RobotRequest, Observation]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RobotGame_ObserveServer = grpc.ServerStreamingServer[Observation]
+
+// RobotGame_ServiceDesc is the grpc.ServiceDesc for RobotGame service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RobotGame_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "robotgame.RobotGame",
+	HandlerType: (*RobotGameServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Join",
+			Handler:    _RobotGame_Join_Handler,
+		},
+		{
+			MethodName: "Resume",
+			Handler:    _RobotGame_Resume_Handler,
+		},
+		{
+			MethodName: "GetRobot",
+			Handler:    _RobotGame_GetRobot_Handler,
+		},
+		{
+			MethodName: "Leave",
+			Handler:    _RobotGame_Leave_Handler,
+		},
+		{
+			MethodName: "Move",
+			Handler:    _RobotGame_Move_Handler,
+		},
+		{
+			MethodName: "Turn",
+			Handler:    _RobotGame_Turn_Handler,
+		},
+		{
+			MethodName: "Attack",
+			Handler:    _RobotGame_Attack_Handler,
+		},
+		{
+			MethodName: "GetState",
+			Handler:    _RobotGame_GetState_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Observe",
+			Handler:       _RobotGame_Observe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "robot_game.proto",
+}
@@ -0,0 +1,230 @@
+package server
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	renderCell  = 40 // pixels along each side of a grid cell
+	renderInset = 6  // pixels between a cell's edge and the robot in it, the direction marker goes here
+	renderScale = 2  // pixels per font pixel
+
+	replayFrameDelay = 25  // hundredths of a second each action is shown for
+	replayEndDelay   = 300 // hundredths of a second the last frame is held before looping
+)
+
+var (
+	renderBackground = color.RGBA{0x1e, 0x1e, 0x1e, 0xff}
+	renderLines      = color.RGBA{0x3a, 0x3a, 0x3a, 0xff}
+	renderDead       = color.RGBA{0x60, 0x60, 0x60, 0xff}
+)
+
+// RenderPNG draws the board as it stands now
+func (g *Game) RenderPNG(w io.Writer) error {
+	world := g.world
+	world.mu.RLock()
+	grid, robots := world.state.Grid, world.copyRobots()
+	world.mu.RUnlock()
+
+	img := image.NewRGBA(boardBounds(grid))
+	drawBoard(img, grid, robots)
+	return png.Encode(w, img)
+}
+
+// RenderGIF animates the replay, one frame per action, looping forever
+func (r *Replay) RenderGIF(w io.Writer) error {
+	frames, err := r.Frames()
+	if err != nil {
+		return err
+	}
+
+	palette := renderPalette()
+	anim := &gif.GIF{}
+	for i, frame := range frames {
+		img := image.NewPaletted(boardBounds(r.Grid), palette)
+		drawBoard(img, r.Grid, frame.Robots)
+
+		delay := replayFrameDelay
+		if i == len(frames)-1 {
+			delay = replayEndDelay
+		}
+		anim.Image = append(anim.Image, img)
+		anim.Delay = append(anim.Delay, delay)
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+// renderPalette is every color drawBoard uses, for GIF frames
+func renderPalette() color.Palette {
+	palette := color.Palette{renderBackground, renderLines, renderDead, color.Black, color.White}
+	for _, c := range robotColors {
+		palette = append(palette, parseColor(c))
+	}
+	return palette
+}
+
+func boardBounds(grid int) image.Rectangle {
+	return image.Rect(0, 0, grid*renderCell+1, grid*renderCell+1)
+}
+
+// drawBoard draws the grid lines then the robots, the dead underneath the living
+func drawBoard(img draw.Image, grid int, robots []Robot) {
+	draw.Draw(img, img.Bounds(), image.NewUniform(renderBackground), image.Point{}, draw.Src)
+
+	size := grid*renderCell + 1
+	for i := 0; i <= grid; i++ {
+		fill(img, image.Rect(i*renderCell, 0, i*renderCell+1, size), renderLines)
+		fill(img, image.Rect(0, i*renderCell, size, i*renderCell+1), renderLines)
+	}
+
+	sort.SliceStable(robots, func(i, j int) bool { return robots[i].Dead && !robots[j].Dead })
+	for _, r := range robots {
+		drawRobot(img, r)
+	}
+}
+
+// drawRobot draws a robot as a square of its color, a marker on the side it faces, and its name
+func drawRobot(img draw.Image, r Robot) {
+	x0, y0 := r.X*renderCell, r.Y*renderCell
+	cx, cy := x0+renderCell/2, y0+renderCell/2
+	body := renderCell - 2*renderInset + 1
+
+	c := parseColor(r.Color)
+	if r.Dead {
+		c = renderDead
+	}
+	fill(img, image.Rect(x0+renderInset, y0+renderInset, x0+renderInset+body, y0+renderInset+body), c)
+
+	if r.Dead {
+		// Cross it out
+		for i := 0; i < body; i++ {
+			img.Set(x0+renderInset+i, y0+renderInset+i, renderBackground)
+			img.Set(x0+renderInset+body-1-i, y0+renderInset+i, renderBackground)
+		}
+	} else {
+		// A triangle in the margin, narrowing towards the edge of the cell
+		for d := 1; d < renderInset; d++ {
+			along := renderCell/2 - renderInset + d
+			for across := d - renderInset; across <= renderInset-d; across++ {
+				switch r.Direction {
+				case North:
+					img.Set(cx+across, cy-along, c)
+				case East:
+					img.Set(cx+along, cy+across, c)
+				case South:
+					img.Set(cx+across, cy+along, c)
+				case West:
+					img.Set(cx-along, cy+across, c)
+				}
+			}
+		}
+	}
+
+	name := []rune(strings.ToUpper(r.Name))
+	if len(name) > 3 {
+		name = name[:3]
+	}
+	width := len(name)*(glyphWidth+1)*renderScale - renderScale
+	drawText(img, string(name), cx-width/2, cy-glyphHeight*renderScale/2, textColor(c))
+}
+
+func fill(img draw.Image, r image.Rectangle, c color.Color) {
+	draw.Draw(img, r, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// parseColor reads a #rrggbb robot color, falling back to white
+func parseColor(hex string) color.RGBA {
+	if len(hex) != 7 || hex[0] != '#' {
+		return color.RGBA{0xff, 0xff, 0xff, 0xff}
+	}
+	rgb, err := strconv.ParseUint(hex[1:], 16, 32)
+	if err != nil {
+		return color.RGBA{0xff, 0xff, 0xff, 0xff}
+	}
+	return color.RGBA{uint8(rgb >> 16), uint8(rgb >> 8), uint8(rgb), 0xff}
+}
+
+// textColor is black on light colors and white on dark ones
+func textColor(c color.RGBA) color.Color {
+	if 299*int(c.R)+587*int(c.G)+114*int(c.B) > 140*1000 {
+		return color.Black
+	}
+	return color.White
+}
+
+// drawText writes s with its top left corner at x, y
+func drawText(img draw.Image, s string, x, y int, c color.Color) {
+	for _, ch := range s {
+		g, ok := glyphs[ch]
+		if !ok {
+			g = glyphs['?']
+		}
+		for row, line := range g {
+			for col, px := range line {
+				if px == '#' {
+					fill(img, image.Rect(x+col*renderScale, y+row*renderScale, x+(col+1)*renderScale, y+(row+1)*renderScale), c)
+				}
+			}
+		}
+		x += (glyphWidth + 1) * renderScale
+	}
+}
+
+const (
+	glyphWidth  = 3
+	glyphHeight = 5
+)
+
+// glyphs is a tiny bitmap font, enough for robot names
+var glyphs = map[rune][glyphHeight]string{
+	'A': {"###", "#.#", "###", "#.#", "#.#"},
+	'B': {"##.", "#.#", "##.", "#.#", "##."},
+	'C': {"###", "#..", "#..", "#..", "###"},
+	'D': {"##.", "#.#", "#.#", "#.#", "##."},
+	'E': {"###", "#..", "##.", "#..", "###"},
+	'F': {"###", "#..", "##.", "#..", "#.."},
+	'G': {"###", "#..", "#.#", "#.#", "###"},
+	'H': {"#.#", "#.#", "###", "#.#", "#.#"},
+	'I': {"###", ".#.", ".#.", ".#.", "###"},
+	'J': {"..#", "..#", "..#", "#.#", "###"},
+	'K': {"#.#", "#.#", "##.", "#.#", "#.#"},
+	'L': {"#..", "#..", "#..", "#..", "###"},
+	'M': {"#.#", "###", "###", "#.#", "#.#"},
+	'N': {"##.", "#.#", "#.#", "#.#", "#.#"},
+	'O': {"###", "#.#", "#.#", "#.#", "###"},
+	'P': {"###", "#.#", "###", "#..", "#.."},
+	'Q': {"###", "#.#", "#.#", "###", "..#"},
+	'R': {"##.", "#.#", "##.", "#.#", "#.#"},
+	'S': {"###", "#..", "###", "..#", "###"},
+	'T': {"###", ".#.", ".#.", ".#.", ".#."},
+	'U': {"#.#", "#.#", "#.#", "#.#", "###"},
+	'V': {"#.#", "#.#", "#.#", "#.#", ".#."},
+	'W': {"#.#", "#.#", "###", "###", "#.#"},
+	'X': {"#.#", "#.#", ".#.", "#.#", "#.#"},
+	'Y': {"#.#", "#.#", ".#.", ".#.", ".#."},
+	'Z': {"###", "..#", ".#.", "#..", "###"},
+	'0': {"###", "#.#", "#.#", "#.#", "###"},
+	'1': {".#.", "##.", ".#.", ".#.", "###"},
+	'2': {"###", "..#", "###", "#..", "###"},
+	'3': {"###", "..#", ".##", "..#", "###"},
+	'4': {"#.#", "#.#", "###", "..#", "..#"},
+	'5': {"###", "#..", "###", "..#", "###"},
+	'6': {"###", "#..", "###", "#.#", "###"},
+	'7': {"###", "..#", "..#", "..#", "..#"},
+	'8': {"###", "#.#", "###", "#.#", "###"},
+	'9': {"###", "#.#", "###", "..#", "###"},
+	' ': {"...", "...", "...", "...", "..."},
+	'-': {"...", "...", "###", "...", "..."},
+	'_': {"...", "...", "...", "...", "###"},
+	'.': {"...", "...", "...", "...", ".#."},
+	'!': {".#.", ".#.", ".#.", "...", ".#."},
+	'?': {"###", "..#", ".##", "...", ".#."},
+}
@@ -0,0 +1,32 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+)
+
+func getStatePNG(g *Game, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	var buf bytes.Buffer
+	if err := g.RenderPNG(&buf); err != nil {
+		return nil, err
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	_, err := buf.WriteTo(w)
+	return nil, err
+}
+
+func getReplayGIF(g *Game, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	_, replay, err := finishedReplay(g, r)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := replay.RenderGIF(&buf); err != nil {
+		return nil, err
+	}
+
+	w.Header().Set("Content-Type", "image/gif")
+	_, err = buf.WriteTo(w)
+	return nil, err
+}
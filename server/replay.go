@@ -0,0 +1,303 @@
+package server
+
+import (
+	"fmt"
+	"time"
+)
+
+// replayVersion is bumped whenever Replay changes in a way older servers can't read
+const replayVersion = 1
+
+// Replay is everything needed to play a round again through the engine: how it started and, in order, what each
+// robot did. Robots are identified by name, their IDs are secrets.
+type Replay struct {
+	Version     int            `json:"version"`
+	Round       int            `json:"round"`
+	Grid        int            `json:"grid"`
+	Delay       time.Duration  `json:"delay"`
+	SpawnPoints []Location     `json:"spawn_points"`
+	Seed        int64          `json:"seed"`   // seed the round started with
+	Tick        int            `json:"tick"`   // tick the round started at
+	Start       time.Time      `json:"start"`  // when the round started
+	Robots      []Robot        `json:"robots"` // as the round started
+	Actions     []ReplayAction `json:"actions"`
+	Final       []Robot        `json:"final"` // as the round ended, or stands now if it hasn't
+}
+
+// ReplayAction is one thing a robot did, the seed changing when the server restarted or the spawn points changing
+type ReplayAction struct {
+	Tick        int        `json:"tick"`
+	Time        time.Time  `json:"time"`
+	Action      string     `json:"action"`
+	Robot       string     `json:"robot,omitempty"`
+	Left        bool       `json:"left,omitempty"`
+	Seed        int64      `json:"seed,omitempty"`
+	SpawnPoints []Location `json:"spawn_points,omitempty"`
+	Result      string     `json:"result"` // how it went at the time
+}
+
+// Replay builds the replay of a round from the journal. The round can still be going, but then the replay gives
+// away its seed, so only finished rounds are served over the API.
+func (g *Game) Replay(round int) (*Replay, error) {
+	if err := g.flush(); err != nil {
+		return nil, err
+	}
+
+	r := &Replay{Version: replayVersion, Round: round, Grid: gridSize, Delay: actionDelay, Actions: []ReplayAction{}}
+
+	// world follows the journal through the round, it's nil until the round starts
+	var world *World
+	if round == 0 {
+		world = newWorld(State{}, nil)
+	}
+	names := map[string]string{}
+
+	since, err := g.roundStart(round)
+	if err != nil {
+		return nil, err
+	}
+	for ended := false; !ended; {
+		page, err := g.store.Events(since, 1000)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range page {
+			ev := &page[i]
+			since = ev.ID
+
+			if ev.Round == round-1 && ev.Action == ActionRound {
+				// The round before ended, setting this one up
+				world = newWorld(*ev.State, ev.Robots)
+				r.Seed, r.Tick, r.Start, r.SpawnPoints = ev.State.Seed, ev.Tick, ev.Time, ev.State.SpawnPoints
+				r.Robots = replayRobots(world)
+				for _, robot := range ev.Robots {
+					names[robot.ID] = robot.Name
+				}
+				continue
+			}
+			if ev.Round != round {
+				continue
+			}
+			if world == nil {
+				return nil, fmt.Errorf("round %d started before the journal did", round)
+			}
+			if r.Start.IsZero() {
+				r.Start = ev.Time
+			}
+
+			a := ReplayAction{Tick: ev.Tick, Time: ev.Time, Action: ev.Action, Left: ev.Left, Result: ev.Result}
+			switch ev.Action {
+			case ActionImport:
+				return nil, fmt.Errorf("round %d was imported part way through", round)
+			case ActionSeed:
+				a.Seed = ev.State.Seed
+			case ActionMap:
+				a.SpawnPoints = ev.State.SpawnPoints
+			case ActionJoin:
+				names[ev.RobotID] = ev.Actor
+				a.Robot = ev.Actor
+			case ActionLeave, ActionMove, ActionTurn, ActionAttack:
+				name, known := names[ev.RobotID]
+				if !known {
+					return nil, fmt.Errorf("%s was on the grid before the journal began", ev.Actor)
+				}
+				a.Robot = name
+			}
+			if ev.Action != ActionDeath && ev.Action != ActionRound && ev.Action != ActionPopulation {
+				// Deaths and new rounds follow from attacks, the engine takes care of them. The population only
+				// shows in the NPCs joining and leaving.
+				r.Actions = append(r.Actions, a)
+			}
+
+			if ev.Action == ActionRound {
+				// It sets up the next round, the robots end this one as they stand
+				ended = true
+				break
+			}
+			world.apply(ev)
+		}
+
+		if len(page) < 1000 {
+			break
+		}
+	}
+
+	if world == nil {
+		return nil, fmt.Errorf("round %d hasn't started", round)
+	}
+	if r.Robots == nil {
+		r.Robots = []Robot{}
+	}
+	r.Final = replayRobots(world)
+	return r, nil
+}
+
+// roundStart returns where to read the journal from for a round: just before the event that ended the round before
+func (g *Game) roundStart(round int) (int, error) {
+	if round == 0 {
+		return 0, nil
+	}
+	rounds, err := g.store.Rounds()
+	if err != nil {
+		return 0, err
+	}
+	for i := len(rounds) - 1; i >= 0; i-- {
+		if rounds[i].Number == round-1 && rounds[i].EventID > 0 {
+			return rounds[i].EventID - 1, nil
+		}
+	}
+	// Rounds recorded before where they ended was, or one that hasn't started
+	return 0, nil
+}
+
+// replayRobots copies the world's robots, naming them instead of giving away their IDs
+func replayRobots(w *World) []Robot {
+	robots := w.copyRobots()
+	for i := range robots {
+		robots[i].ID = robots[i].Name
+	}
+	return robots
+}
+
+// ReplayFrame is how the grid stands after an action
+type ReplayFrame struct {
+	Action *ReplayAction `json:"action"` // nil for the start of the round
+	Robots []Robot       `json:"robots"`
+	Killed string        `json:"killed,omitempty"` // who the action killed
+	Winner string        `json:"winner,omitempty"` // who won, if the action ended the round
+}
+
+// Simulate plays the replay through the engine and returns the robots as they end up
+func (r *Replay) Simulate() ([]Robot, error) {
+	frames, err := r.Frames()
+	if err != nil {
+		return nil, err
+	}
+	return frames[len(frames)-1].Robots, nil
+}
+
+// Validate checks a replay, as read from a file, could have come from this server
+func (r *Replay) Validate() error {
+	if r.Version != replayVersion {
+		return fmt.Errorf("unsupported replay version %d, expected %d", r.Version, replayVersion)
+	}
+	if r.Grid != gridSize {
+		return fmt.Errorf("replay is for a %d grid but this server plays on %d", r.Grid, gridSize)
+	}
+	if err := validateSpawnPoints(r.SpawnPoints); err != nil {
+		return err
+	}
+	if err := validateRobots(r.Robots); err != nil {
+		return fmt.Errorf("as the round started: %v", err)
+	}
+	if err := validateRobots(r.Final); err != nil {
+		return fmt.Errorf("as the round ended: %v", err)
+	}
+
+	for _, a := range r.Actions {
+		switch a.Action {
+		case ActionSeed, ActionJoin, ActionLeave, ActionMove, ActionTurn, ActionAttack:
+		case ActionMap:
+			if err := validateSpawnPoints(a.SpawnPoints); err != nil {
+				return fmt.Errorf("tick %d: %v", a.Tick, err)
+			}
+		default:
+			return fmt.Errorf("tick %d: unknown action %q", a.Tick, a.Action)
+		}
+	}
+	return nil
+}
+
+// Frames plays the replay through the engine, returning the grid as the round started then after every action
+func (r *Replay) Frames() ([]ReplayFrame, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	clock := NewFakeClock(r.Start)
+	g := &Game{store: NewMemoryStore(), clock: clock}
+	g.world = newWorld(State{Round: r.Round, Seed: r.Seed, Tick: r.Tick, SpawnPoints: r.SpawnPoints}, r.Robots)
+	w := g.world
+
+	// view follows the engine's events except the one setting up the next round, so the last frame shows the round
+	// as it ended rather than everyone respawned
+	view := newWorld(w.state, r.Robots)
+	frames := []ReplayFrame{{Robots: replayRobots(view)}}
+	for i := range r.Actions {
+		a := &r.Actions[i]
+		clock.Set(a.Time)
+		w.state.Tick = a.Tick - 1
+
+		var err error
+		switch a.Action {
+		case ActionSeed:
+			s := w.state
+			s.Seed = a.Seed
+			err = g.apply(&Event{Action: ActionSeed, State: &s})
+		case ActionMap:
+			err = g.setSpawnPoints(a.SpawnPoints)
+		case ActionJoin:
+			_, err = g.join(a.Robot, a.Robot, "")
+		case ActionLeave:
+			err = g.leave(a.Robot)
+		default:
+			err = g.act(&Event{Action: a.Action, RobotID: a.Robot, Left: a.Left})
+		}
+
+		result := "ok"
+		if err != nil {
+			result = err.Error()
+		}
+		if result != a.Result {
+			return nil, fmt.Errorf("tick %d: %s by %s went %q but replays as %q", a.Tick, a.Action, a.Robot, a.Result, result)
+		}
+
+		// Nothing writes this game's journal, so what the action led to is still pending
+		frame := ReplayFrame{Action: a}
+		events := g.journal.take()
+		for i := range events {
+			ev := &events[i]
+			switch ev.Action {
+			case ActionDeath:
+				frame.Killed = ev.Target
+			case ActionRound:
+				frame.Winner = ev.Actor
+				continue
+			}
+			view.apply(ev)
+		}
+		frame.Robots = replayRobots(view)
+		frames = append(frames, frame)
+	}
+
+	return frames, nil
+}
+
+// Verify simulates the replay and checks the robots end up as recorded
+func (r *Replay) Verify() error {
+	final, err := r.Simulate()
+	if err != nil {
+		return err
+	}
+
+	recorded := map[string]Robot{}
+	for _, robot := range r.Final {
+		recorded[robot.ID] = robot
+	}
+	if len(final) != len(recorded) {
+		return fmt.Errorf("%d robots recorded but %d after replaying", len(recorded), len(final))
+	}
+
+	for _, got := range final {
+		want, exists := recorded[got.ID]
+		if !exists {
+			return fmt.Errorf("%s wasn't recorded", got.Name)
+		}
+		if got.X != want.X || got.Y != want.Y || got.Direction != want.Direction || got.Score != want.Score || got.Dead != want.Dead {
+			return fmt.Errorf("%s recorded at %d,%d facing %d with score %d dead=%t but replays at %d,%d facing %d with score %d dead=%t",
+				got.Name, want.X, want.Y, want.Direction, want.Score, want.Dead, got.X, got.Y, got.Direction, got.Score, got.Dead)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+func getRounds(g *Game, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	return g.store.Rounds()
+}
+
+func getReplay(g *Game, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	round, replay, err := finishedReplay(g, r)
+	if err != nil {
+		return nil, err
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="round-%d.replay.json"`, round))
+	return replay, nil
+}
+
+// finishedReplay is the replay of the round in the request, as long as it's over. The seed it was played with is in
+// there, and while the round goes on that would tell a player where everyone is going to spawn.
+func finishedReplay(g *Game, r *http.Request) (int, *Replay, error) {
+	round, err := strconv.Atoi(mux.Vars(r)["round"])
+	if err != nil {
+		return 0, nil, fmt.Errorf("round must be a number")
+	}
+
+	state, err := g.State()
+	if err != nil {
+		return 0, nil, err
+	}
+	if round == state.Round {
+		return 0, nil, fmt.Errorf("round %d isn't over yet", round)
+	}
+
+	replay, err := g.Replay(round)
+	return round, replay, err
+}
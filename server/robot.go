@@ -2,189 +2,299 @@ package server
 
 import (
 	"fmt"
-	"time"
+	"strings"
 
+	"github.com/fanatic/robot-game/server/wire"
 	"github.com/google/uuid"
 )
 
 // Robot is the player
-type Robot struct {
-	ID        string       `json:"id,omitempty"` // also the secret
-	CreatedAt time.Time    `json:"created_at"`
-	Name      string       `json:"name"`
-	X         int          `json:"x"`
-	Y         int          `json:"y"`
-	Color     string       `json:"color"`
-	Direction int          `json:"direction"`
-	Vision    int          `json:"vision"`
-	Score     int          `json:"score"`
-	Dead      bool         `json:"dead"`
-	InRange   []ShortRobot `json:"robots_in_range"`
-}
+type Robot = wire.Robot
 
 // ShortRobot is used when sharing enemy robots
-type ShortRobot struct {
-	Name      string `json:"name"`
-	X         int    `json:"x"`
-	Y         int    `json:"y"`
-	Direction int    `json:"direction"`
-}
+type ShortRobot = wire.ShortRobot
 
-// NewRobot creates a new robot, saves to the db, and returns it
+// NewRobot creates a new robot, adds it to the world, and returns it
 func (g *Game) NewRobot(name string) (*Robot, error) {
-	s, err := g.State()
+	name, err := PlayerName(name)
 	if err != nil {
 		return nil, err
 	}
+	id, _ := uuid.NewRandom()
+
+	w := g.world
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return g.join(name, id.String(), "")
+}
+
+// join is NewRobot with a chosen ID, played by the server if npc is set. The caller must hold the world's lock.
+func (g *Game) join(name, id, npc string) (*Robot, error) {
+	w := g.world
 
 	// Limit robots by name
 	robotCount := 0
-	for _, robot := range s.Robots {
+	for _, robot := range w.robots {
 		if robot.Name == name {
 			robotCount++
 		}
 	}
 	if robotCount >= robotLimit {
-		return nil, fmt.Errorf("no more robots - you're at the limit")
+		return nil, wire.ErrLimit
 	}
 
-	id, _ := uuid.NewRandom()
-	r := Robot{
-		ID:        id.String(),
-		CreatedAt: time.Now(),
-		Color:     findFirstUnusedColor(s.Robots),
-		Name:      name,
-		Vision:    4,
-		Score:     0,
+	x, y, direction, err := w.spawnLocation(w.rng())
+	if err != nil {
+		return nil, err
 	}
-	r.X, r.Y, r.Direction = s.randomFreeLocation()
 
-	if err := g.db.Save(&r); err != nil {
-		return nil, err
+	r := Robot{
+		ID:             id,
+		CreatedAt:      g.clock.Now(),
+		Color:          findFirstUnusedColor(w.copyRobots()),
+		Name:           name,
+		X:              x,
+		Y:              y,
+		Direction:      direction,
+		Vision:         4,
+		Score:          0,
+		ProtectedUntil: g.clock.Now().Add(spawnProtection * actionDelay),
+		NPC:            npc,
 	}
 
-	s, err = g.State()
-	if err != nil {
+	if err := g.apply(&Event{Action: ActionJoin, Actor: name, RobotID: r.ID, Robots: []Robot{r}}); err != nil {
 		return nil, err
 	}
 
-	r.InRange = s.RobotsInRange(&r)
+	r.InRange = w.robotsInRange(&r)
 
 	return &r, nil
 }
 
-// Robot gets an existing robot directly from the db and returns it
+// PlayerName checks name is one a player can take and returns it the way the game spells it, in capitals. Every
+// front end joins through NewRobot, so they all agree. Names with digits in are kept for NPCs.
+func PlayerName(name string) (string, error) {
+	if len(name) != 2 {
+		return "", fmt.Errorf("name must be exactly 2 characters")
+	}
+	name = strings.ToUpper(name)
+	for _, c := range name {
+		if c < 'A' || c > 'Z' {
+			return "", fmt.Errorf("name must be letters - names with digits are kept for npcs")
+		}
+	}
+	return name, nil
+}
+
+// Robot gets an existing robot from the world and returns it
 func (g *Game) Robot(id string) (*Robot, error) {
-	var r Robot
-	if err := g.db.One("ID", id, &r); err != nil {
+	w := g.world
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	r, err := w.living(id)
+	if err != nil {
 		return nil, err
 	}
+	r.InRange = w.robotsInRange(&r)
 
-	if r.Dead {
-		return nil, fmt.Errorf("this robot be dead")
-	}
+	return &r, nil
+}
 
-	s, err := g.State()
+// ResumeRobot reattaches a client to a robot it already has, dead or alive. It doesn't join anything, so it
+// doesn't count against robotLimit.
+func (g *Game) ResumeRobot(id string) (*Robot, error) {
+	w := g.world
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	r, err := w.robot(id)
 	if err != nil {
 		return nil, err
 	}
-	r.InRange = s.RobotsInRange(&r)
+	r.InRange = w.robotsInRange(&r)
 
 	return &r, nil
 }
 
-// DeleteRobot from the db
+// DeleteRobot from the world
 func (g *Game) DeleteRobot(id string) error {
-	return g.db.DeleteStruct(&Robot{ID: id})
+	w := g.world
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return g.leave(id)
+}
+
+// leave is DeleteRobot for callers already holding the world's lock
+func (g *Game) leave(id string) error {
+	r, err := g.world.robot(id)
+	if err != nil {
+		return err
+	}
+	return g.apply(&Event{Action: ActionLeave, Actor: r.Name, RobotID: id, Deleted: []string{id}})
 }
 
-// Move a robot in the db
+// Move a robot forward one cell
 func (g *Game) Move(id string) error {
-	time.Sleep(actionDelay)
+	g.clock.Sleep(actionDelay)
+
+	w := g.world
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return g.act(&Event{Action: ActionMove, RobotID: id})
+}
+
+// Turn a robot left (true) or right (false)
+func (g *Game) Turn(id string, direction bool) error {
+	g.clock.Sleep(actionDelay)
+
+	w := g.world
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return g.act(&Event{Action: ActionTurn, RobotID: id, Left: direction})
+}
 
-	r, err := g.Robot(id)
+// Attack whatever robot is in front
+func (g *Game) Attack(id string) error {
+	g.clock.Sleep(actionDelay)
+
+	w := g.world
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return g.act(&Event{Action: ActionAttack, RobotID: id})
+}
+
+// act carries out the action in ev for ev.RobotID and journals it, along with anything that followed from it. Actions
+// the game refuses are journaled too, with why as their Result, as long as there's a robot to put them down to. The
+// caller must hold the world's lock.
+func (g *Game) act(ev *Event) error {
+	var err error
+	switch ev.Action {
+	case ActionMove:
+		err = g.move(ev)
+	case ActionTurn:
+		err = g.turn(ev)
+	case ActionAttack:
+		err = g.attack(ev)
+	default:
+		err = fmt.Errorf("unknown action %q", ev.Action)
+	}
 	if err != nil {
+		if ev.Actor == "" {
+			return err
+		}
+		// Refused actions leave ev.Robots empty, so they go in the history without changing the world
+		ev.Result = err.Error()
+		if applyErr := g.apply(ev); applyErr != nil {
+			return applyErr
+		}
+		return err
+	}
+	if err := g.apply(ev); err != nil {
 		return err
 	}
 
-	s, err := g.State()
+	if ev.Action == ActionAttack {
+		// The attack already took the victim down, this just puts it in the history as its own entry
+		victim := ev.Robots[1]
+		death := &Event{Action: ActionDeath, Actor: ev.Actor, Target: victim.Name, RobotID: ev.RobotID}
+		if err := g.apply(death); err != nil {
+			return err
+		}
+		return g.updateRound()
+	}
+	return nil
+}
+
+func (g *Game) move(ev *Event) error {
+	w := g.world
+	r, err := w.robot(ev.RobotID)
 	if err != nil {
 		return err
 	}
+	ev.Actor = r.Name
+	if r.Dead {
+		return errDead
+	}
 
 	l, exists := adjacentGridLocations(r.X, r.Y)[r.Direction]
 	if !exists {
 		return fmt.Errorf("unknown direction")
 	}
 
-	if l.X < 0 || l.X == s.Grid || l.Y < 0 || l.Y == s.Grid {
-		return fmt.Errorf("off the grid")
-	}
-
-	if robot := s.locateRobot(l.X, l.Y); robot != nil && !robot.Dead {
-		return fmt.Errorf("something's in the way")
+	if !w.onGrid(l) {
+		return wire.ErrOffGrid
 	}
 
-	if l.X != r.X {
-		return g.db.UpdateField(r, "X", l.X)
-	} else if l.Y != r.Y {
-		return g.db.UpdateField(r, "Y", l.Y)
+	if robot := w.robotAt(l.X, l.Y); robot != nil {
+		ev.Target = robot.Name
+		return wire.ErrBlocked
 	}
 
+	r.X, r.Y = l.X, l.Y
+	ev.Robots = []Robot{r}
 	return nil
 }
 
-// Turn a robot in the db
-func (g *Game) Turn(id string, direction bool) error {
-	time.Sleep(actionDelay)
-
-	r, err := g.Robot(id)
+func (g *Game) turn(ev *Event) error {
+	r, err := g.world.robot(ev.RobotID)
 	if err != nil {
 		return err
 	}
+	ev.Actor = r.Name
+	if r.Dead {
+		return errDead
+	}
 
 	newDirection := (r.Direction + 1) % 4
-	if direction {
+	if ev.Left {
 		newDirection = (r.Direction + 3) % 4 // -1
 	}
-	return g.db.UpdateField(r, "Direction", newDirection)
+	r.Direction = newDirection
+	ev.Robots = []Robot{r}
+	return nil
 }
 
-// Attack a robot via the db
-func (g *Game) Attack(id string) error {
-	time.Sleep(actionDelay)
-
-	r, err := g.Robot(id)
+// attack leaves the attacker and its victim in ev.Robots
+func (g *Game) attack(ev *Event) error {
+	w := g.world
+	r, err := w.robot(ev.RobotID)
 	if err != nil {
 		return err
 	}
-
-	s, err := g.State()
-	if err != nil {
-		return err
+	ev.Actor = r.Name
+	if r.Dead {
+		return errDead
 	}
 
 	l := adjacentGridLocations(r.X, r.Y)
-	robot := s.locateRobot(l[r.Direction].X, l[r.Direction].Y)
+	robot := w.robotAt(l[r.Direction].X, l[r.Direction].Y)
+	if robot == nil {
+		return wire.ErrMissed
+	}
 
-	if robot != nil {
-		if robot.Dead {
-			return fmt.Errorf("how rude to attack a dead robot")
-		}
-		if err := g.db.UpdateField(r, "Score", r.Score+10); err != nil {
-			return err
-		}
-		if err := g.db.UpdateField(robot, "Dead", true); err != nil {
-			return err
-		}
-		return g.UpdateRound()
+	ev.Target = robot.Name
+	if g.clock.Now().Before(robot.ProtectedUntil) {
+		return wire.ErrProtected
 	}
 
-	return fmt.Errorf("swwwing and a missss")
+	victim := *robot
+	victim.Dead = true
+	r.Score += 10
+	ev.Robots = []Robot{r, victim}
+	return nil
 }
 
+// robotColors are handed out to robots in order as they join
+var robotColors = []string{"#e6194b", "#3cb44b", "#ffe119", "#4363d8", "#f58231", "#911eb4", "#46f0f0", "#f032e6", "#bcf60c", "#fabebe", "#008080", "#e6beff", "#9a6324", "#fffac8", "#800000", "#aaffc3", "#808000", "#ffd8b1", "#000075", "#808080", "#ffffff", "#000000"}
+
 func findFirstUnusedColor(robots []Robot) string {
-	colors := []string{"#e6194b", "#3cb44b", "#ffe119", "#4363d8", "#f58231", "#911eb4", "#46f0f0", "#f032e6", "#bcf60c", "#fabebe", "#008080", "#e6beff", "#9a6324", "#fffac8", "#800000", "#aaffc3", "#808000", "#ffd8b1", "#000075", "#808080", "#ffffff", "#000000"}
+	colors := robotColors
 
 	for _, color := range colors {
 		isUsed := false
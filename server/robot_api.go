@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"strings"
 
 	"github.com/gorilla/mux"
 )
@@ -16,11 +15,7 @@ func postRobot(g *Game, w http.ResponseWriter, r *http.Request) (interface{}, er
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
 		return nil, fmt.Errorf("invalid payload body {\"name\": \"JP\"}")
 	}
-	if len(payload.Name) != 2 {
-		return nil, fmt.Errorf("name must be exactly 2 characters")
-	}
-
-	robot, err := g.NewRobot(strings.ToUpper(payload.Name))
+	robot, err := g.NewRobot(payload.Name)
 	if err != nil {
 		return nil, err
 	}
@@ -40,12 +35,30 @@ func deleteRobot(g *Game, w http.ResponseWriter, r *http.Request) (interface{},
 	return nil, nil
 }
 
+func postResume(g *Game, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	id := mux.Vars(r)["id"]
+
+	robot, err := g.ResumeRobot(id)
+	if err != nil {
+		return nil, err
+	}
+	return robot, nil
+}
+
 func getRobot(g *Game, w http.ResponseWriter, r *http.Request) (interface{}, error) {
 	id := mux.Vars(r)["id"]
 
+	if wantsASCII(r) {
+		text, err := g.RenderRobotASCII(id)
+		if err != nil {
+			return nil, err
+		}
+		return writeASCII(w, text)
+	}
+
 	robot, err := g.Robot(id)
 	if err != nil {
-		json.NewEncoder(w).Encode(err)
+		return nil, err
 	}
 	return robot, nil
 }
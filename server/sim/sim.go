@@ -0,0 +1,231 @@
+// Package sim plays matches between bots entirely in process, with no HTTP and no waiting between actions, so a
+// strategy can be tried thousands of times in the time a real match takes.
+package sim
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fanatic/robot-game/server"
+	"github.com/fanatic/robot-game/server/bot"
+)
+
+// NewBrain makes a fresh Brain for each match. Brains with any randomness should draw it from seed, so the same
+// seed plays out the same. Brains that are also io.Closers are closed when their match ends.
+type NewBrain func(seed int64) bot.Brain
+
+// Config is how a simulation runs
+type Config struct {
+	Matches    int   // how many to play, 100 by default
+	Seed       int64 // match i plays with Seed+i
+	MaxActions int   // each bot gets this many turns before the match is called a draw, 500 by default
+}
+
+// Stats is how a bot did over every match
+type Stats struct {
+	Name    string
+	Wins    int
+	Draws   int // matches nobody won
+	Kills   int
+	Deaths  int
+	Score   int
+	Actions int
+}
+
+// Results of a simulation
+type Results struct {
+	Matches int
+	Draws   int
+	Bots    []Stats // best first
+}
+
+// Sim plays matches between registered bots
+type Sim struct {
+	config Config
+	names  []string
+	brains map[string]NewBrain
+}
+
+// New returns a Sim with no bots
+func New(config Config) *Sim {
+	if config.Matches == 0 {
+		config.Matches = 100
+	}
+	if config.MaxActions == 0 {
+		config.MaxActions = 500
+	}
+	return &Sim{config: config, brains: map[string]NewBrain{}}
+}
+
+// Register adds a bot to every match. Names are robot names, unique and two characters.
+func (s *Sim) Register(name string, newBrain NewBrain) error {
+	name, err := server.PlayerName(name)
+	if err != nil {
+		return err
+	}
+	if _, exists := s.brains[name]; exists {
+		return fmt.Errorf("%s is already registered", name)
+	}
+	s.names = append(s.names, name)
+	s.brains[name] = newBrain
+	return nil
+}
+
+// Run plays every match
+func (s *Sim) Run() (*Results, error) {
+	if len(s.names) < 2 {
+		return nil, errors.New("a match needs at least 2 bots")
+	}
+
+	stats := map[string]*Stats{}
+	for _, name := range s.names {
+		stats[name] = &Stats{Name: name}
+	}
+	results := &Results{Matches: s.config.Matches}
+
+	for i := 0; i < s.config.Matches; i++ {
+		drawn, err := s.match(i, stats)
+		if err != nil {
+			return nil, fmt.Errorf("match %d: %v", i, err)
+		}
+		if drawn {
+			results.Draws++
+		}
+	}
+
+	for _, name := range s.names {
+		results.Bots = append(results.Bots, *stats[name])
+	}
+	sort.SliceStable(results.Bots, func(i, j int) bool {
+		a, b := results.Bots[i], results.Bots[j]
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		return a.Score > b.Score
+	})
+	return results, nil
+}
+
+// match plays one round to its end, or to MaxActions, and adds how it went to stats
+func (s *Sim) match(i int, stats map[string]*Stats) (drawn bool, err error) {
+	seed := s.config.Seed + int64(i)
+	g, err := server.NewGame(server.WithSeed(seed), server.WithClock(server.NewFakeClock(time.Unix(0, 0).UTC())), server.WithoutLoops())
+	if err != nil {
+		return false, err
+	}
+	defer g.Close()
+
+	// Take turns joining and acting first, so nobody always gets the best spawn
+	order := make([]string, len(s.names))
+	for j := range s.names {
+		order[j] = s.names[(i+j)%len(s.names)]
+	}
+
+	ids := map[string]string{}
+	brains := map[string]bot.Brain{}
+	seeds := rand.New(rand.NewSource(seed))
+	for _, name := range order {
+		r, err := g.NewRobot(name)
+		if err != nil {
+			return false, err
+		}
+		ids[name] = r.ID
+		brains[name] = s.brains[name](seeds.Int63())
+		if c, ok := brains[name].(io.Closer); ok {
+			// Brains that run programs stop them after each match
+			defer c.Close()
+		}
+	}
+
+	state, err := g.State()
+	if err != nil {
+		return false, err
+	}
+	v := bot.View{Grid: state.Grid, Round: state.Round}
+
+	over := false
+	for turn := 0; turn < s.config.MaxActions && !over; turn++ {
+		for _, name := range order {
+			r, err := g.Robot(ids[name])
+			if err != nil {
+				// Dead
+				continue
+			}
+			v.Robot = *r
+			action := brains[name].Decide(v)
+			stats[name].Actions++
+
+			switch action {
+			case bot.Move:
+				err = g.Move(r.ID)
+			case bot.TurnLeft:
+				err = g.Turn(r.ID, true)
+			case bot.TurnRight:
+				err = g.Turn(r.ID, false)
+			case bot.Attack:
+				if err = g.Attack(r.ID); err == nil {
+					// Only a kill can end the round
+					if now, err := g.State(); err == nil && now.Round != state.Round {
+						over = true
+					}
+				}
+			}
+			if over {
+				break
+			}
+		}
+	}
+
+	for since := 0; ; {
+		events, next, err := g.Events(server.EventFilter{}, since, 1000)
+		if err != nil {
+			return false, err
+		}
+		if len(events) == 0 {
+			break
+		}
+		since = next
+
+		for _, ev := range events {
+			switch ev.Action {
+			case server.ActionDeath:
+				stats[ev.Actor].Kills++
+				stats[ev.Target].Deaths++
+			case server.ActionAttack:
+				if ev.Result == "ok" {
+					stats[ev.Actor].Score += 10
+				}
+			case server.ActionRound:
+				stats[ev.Actor].Wins++
+				stats[ev.Actor].Score += 100
+			}
+		}
+	}
+
+	if !over {
+		// Nobody won, so it's a draw for everyone who played it, dead or alive
+		for _, name := range s.names {
+			stats[name].Draws++
+		}
+	}
+	return !over, nil
+}
+
+// WriteTable prints the results as a table, best bot first
+func (r *Results) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "bot\twins\twin%%\tdraws\tkills\tdeaths\tscore\tactions\t\n")
+	for _, b := range r.Bots {
+		fmt.Fprintf(tw, "%s\t%d\t%.1f\t%d\t%d\t%d\t%d\t%d\t\n", b.Name, b.Wins, 100*float64(b.Wins)/float64(r.Matches), b.Draws, b.Kills, b.Deaths, b.Score, b.Actions)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%d matches, %d drawn\n", r.Matches, r.Draws)
+	return err
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"net"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// ServeSSH lets anyone with an SSH client play the terminal game, no install needed: ssh -p PORT host. Players are
+// known by their public key, so whichever machine they connect from they get the same robot back as long as they
+// bring their key. It returns when l is closed, or closes l when the game does.
+func (g *Game) ServeSSH(l net.Listener, hostKey ssh.Signer) error {
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			// Any key will do, it's an identity rather than a credential
+			return &ssh.Permissions{Extensions: map[string]string{"key": ssh.FingerprintSHA256(key)}}, nil
+		},
+	}
+	config.AddHostKey(hostKey)
+
+	go func() {
+		<-g.done
+		l.Close()
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			select {
+			case <-g.done:
+				return nil
+			default:
+				return err
+			}
+		}
+		go g.serveSSHConn(conn, config)
+	}
+}
+
+func (g *Game) serveSSHConn(conn net.Conn, config *ssh.ServerConfig) {
+	defer conn.Close()
+
+	sconn, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		log.Printf("ssh at=handshake addr=%s err=%q\n", conn.RemoteAddr(), err)
+		return
+	}
+	key := sconn.Permissions.Extensions["key"]
+	log.Printf("ssh at=connect addr=%s key=%s\n", conn.RemoteAddr(), key)
+	go ssh.DiscardRequests(requests)
+
+	for nc := range channels {
+		if nc.ChannelType() != "session" {
+			nc.Reject(ssh.UnknownChannelType, "only sessions are supported")
+			continue
+		}
+		ch, requests, err := nc.Accept()
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		go g.serveSSHSession(ch, requests, key)
+	}
+	log.Printf("ssh at=disconnect addr=%s key=%s\n", conn.RemoteAddr(), key)
+}
+
+// serveSSHSession plays the terminal game once the client asks for a shell
+func (g *Game) serveSSHSession(ch ssh.Channel, requests <-chan *ssh.Request, key string) {
+	defer ch.Close()
+
+	shell := make(chan bool, 1)
+	go func() {
+		defer close(shell)
+		for req := range requests {
+			ok := false
+			switch req.Type {
+			case "pty-req", "window-change", "env":
+				// The game draws the same whatever the terminal
+				ok = true
+			case "shell":
+				ok = true
+				select {
+				case shell <- true:
+				default:
+				}
+			}
+			if req.WantReply {
+				req.Reply(ok, nil)
+			}
+		}
+	}()
+
+	if _, ok := <-shell; !ok {
+		return
+	}
+	status := []byte{0, 0, 0, 0}
+	if err := g.PlayTerminal(ch, key); err != nil {
+		fmt.Fprintf(ch, "%v\r\n", err)
+		status[3] = 1
+	}
+	ch.SendRequest("exit-status", false, status)
+}
@@ -3,46 +3,38 @@ package server
 import (
 	"time"
 
-	"github.com/asdine/storm"
+	"github.com/fanatic/robot-game/server/wire"
 )
 
 //const actionDelay = 30 * time.Second
 const actionDelay = 30 * time.Millisecond
 const robotLimit = 1
+const gridSize = 16
+
+// spawnProtection is how many action delays a freshly spawned robot can't be attacked for
+const spawnProtection = 3
 
 // State saves the current round to the db to allow for restarts
-type State struct {
-	// Saved values
-	ID    int `json:"-"`
-	Round int `json:"round"`
-
-	// Values not saved
-	Grid   int     `json:"grid"`
-	Robots []Robot `json:"robots"`
-
-	// Values returned to UI only
-	CurrentDelay      time.Duration `json:"delay"`
-	CurrentRobotLimit int           `json:"robot_limit"`
+type State = wire.State
+
+// Round is the record of a finished round
+type Round struct {
+	ID      int       `json:"id" storm:"id,increment"`
+	Number  int       `json:"round"`
+	Seed    int64     `json:"seed"`
+	Winner  string    `json:"winner"`
+	EndedAt time.Time `json:"ended_at"`
+	EventID int       `json:"event_id"` // the event that ended it
 }
 
-// State returns state from db
+// State returns a copy of the current state of the world
 func (g *Game) State() (*State, error) {
-	var st []State
-	if err := g.db.All(&st); err != nil && err != storm.ErrNotFound {
-		return nil, err
-	}
-	var state State
-	if len(st) == 1 {
-		state = st[0]
-	}
-
-	var robots = make([]Robot, 0)
-	if err := g.db.All(&robots); err != nil && err != storm.ErrNotFound {
-		return nil, err
-	}
+	w := g.world
+	w.mu.RLock()
+	defer w.mu.RUnlock()
 
-	state.Grid = 16
-	state.Robots = robots
+	state := w.state
+	state.Robots = w.copyRobots()
 	state.CurrentDelay = actionDelay
 	state.CurrentRobotLimit = robotLimit
 
@@ -51,74 +43,59 @@ func (g *Game) State() (*State, error) {
 
 // UpdateRound checks if the round is over, and starts a new one
 func (g *Game) UpdateRound() error {
-	s, err := g.State()
-	if err != nil {
-		return err
-	}
+	w := g.world
+	w.mu.Lock()
+	defer w.mu.Unlock()
 
-	if len(s.Robots) <= 1 {
+	return g.updateRound()
+}
+
+// updateRound is UpdateRound for callers already holding the world's lock
+func (g *Game) updateRound() error {
+	w := g.world
+	if len(w.robots) <= 1 {
 		return nil
 	}
 
-	alive := s.robotsAlive()
-	if len(alive) != 1 {
+	if len(w.cells) != 1 {
 		return nil
 	}
+	var winner *Robot
+	for _, robot := range w.cells {
+		winner = robot
+	}
 
 	// Round Over!
 
-	s.ID = 1 // hardcode id so there can only be one state
+	s := w.state
 	s.Round = s.Round + 1
-	if err := g.db.Save(s); err != nil {
-		return err
-	}
-
-	for _, robot := range s.Robots {
-		robot.Dead = false // see next comment
-		robot.X, robot.Y, robot.Direction = s.randomFreeLocation()
-		if robot.ID == alive[0].ID {
-			// Winner Winner, Chicken Dinner
-			robot.Score += 100
+	rng := w.rng()
+	s.Seed = rng.Int63()
+
+	// Respawn onto an empty board so each robot only has to keep its distance from those already placed
+	board := newWorld(s, nil)
+	for _, robot := range w.copyRobots() {
+		x, y, direction, err := board.spawnLocation(rng)
+		if err == wire.ErrGridFull && len(s.SpawnPoints) > 0 {
+			// More robots than the map has spawn points, the rest come back wherever is furthest from everyone
+			x, y, direction, err = board.furthestCell(rng, board.cellList())
 		}
-		if err := g.db.Update(&robot); err != nil {
+		if err != nil {
 			return err
 		}
-		// Update won't save zero-value fields, so do it explicitly for dead and ignore issues with x/y/direction being 0
-		if err := g.db.UpdateField(&robot, "Dead", false); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-func (s *State) robotsAlive() []Robot {
-	alive := []Robot{}
-	for _, robot := range s.Robots {
-		if !robot.Dead {
-			alive = append(alive, robot)
-		}
-	}
-	return alive
-}
-
-func (s *State) locateRobot(x, y int) *Robot {
-	for _, robot := range s.Robots {
-		if robot.X == x && robot.Y == y {
-			return &robot
+		robot.Dead = false
+		robot.X, robot.Y, robot.Direction = x, y, direction
+		robot.ProtectedUntil = g.clock.Now().Add(spawnProtection * actionDelay)
+		if robot.ID == winner.ID {
+			// Winner Winner, Chicken Dinner
+			robot.Score += 100
 		}
+		board.put(robot)
 	}
 
-	return nil
-}
-
-// RobotsInRange returns a list of robots within the vision of the current robot
-func (s *State) RobotsInRange(r *Robot) []ShortRobot {
-	// TODO(jp): support more than vision=4
-	inRange := []ShortRobot{}
-	for _, l := range adjacentGridLocations(r.X, r.Y) {
-		if robot := s.locateRobot(l.X, l.Y); robot != nil && !robot.Dead {
-			inRange = append(inRange, ShortRobot{Name: robot.Name, X: robot.X, Y: robot.Y, Direction: robot.Direction})
-		}
+	// Only recorded once the next round is sure to start
+	if err := g.store.SaveRound(&Round{Number: w.state.Round, Seed: w.state.Seed, Winner: winner.Name, EndedAt: g.clock.Now(), EventID: w.eventID + 1}); err != nil {
+		return err
 	}
-	return inRange
+	return g.apply(&Event{Action: ActionRound, Actor: winner.Name, Robots: board.copyRobots(), State: &s})
 }
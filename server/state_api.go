@@ -3,12 +3,18 @@ package server
 import "net/http"
 
 func getState(g *Game, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	if wantsASCII(r) {
+		text, err := g.RenderASCII()
+		if err != nil {
+			return nil, err
+		}
+		return writeASCII(w, text)
+	}
+
 	s, err := g.State()
 	if err != nil {
 		return nil, err
 	}
-	for i := range s.Robots {
-		s.Robots[i].ID = ""
-	}
-	return s, nil
+	public := s.Public()
+	return &public, nil
 }
@@ -0,0 +1,161 @@
+package server
+
+import (
+	"sort"
+	"sync"
+)
+
+// Store persists the game. The world itself lives in memory, so a store only has to keep periodic snapshots of it,
+// the journal of events applied since, as much of the journal before as there's history for, and the finished rounds.
+type Store interface {
+	// LoadSnapshot returns the last saved state and robots, and the ID of the last event they include
+	LoadSnapshot() (*State, []Robot, int, error)
+	// SaveSnapshot replaces the saved state and robots
+	SaveSnapshot(s *State, robots []Robot, eventID int) error
+
+	// AppendEvent journals an event. Its ID is already assigned and higher than any before it.
+	AppendEvent(ev *Event) error
+	// Events returns up to limit events after since, oldest first. A limit of 0 returns them all.
+	Events(since, limit int) ([]Event, error)
+	// DropEvents removes the events up to and including through from the journal
+	DropEvents(through int) error
+
+	SaveRound(r *Round) error
+	Rounds() ([]Round, error)
+
+	// Account returns the account for key, or nil if there isn't one
+	Account(key string) (*Account, error)
+	// SaveAccount adds or replaces an account
+	SaveAccount(a *Account) error
+	// Accounts returns every account
+	Accounts() ([]Account, error)
+
+	Close() error
+}
+
+// MemoryStore keeps everything in process, for tests and ephemeral arenas
+type MemoryStore struct {
+	mu       sync.Mutex
+	state    State
+	robots   []Robot
+	eventID  int
+	events   []Event
+	rounds   []Round
+	accounts map[string]Account
+}
+
+// NewMemoryStore returns an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{accounts: map[string]Account{}}
+}
+
+// LoadSnapshot returns the last saved state and robots
+func (m *MemoryStore) LoadSnapshot() (*State, []Robot, int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := m.state
+	return &s, append([]Robot{}, m.robots...), m.eventID, nil
+}
+
+// SaveSnapshot replaces the saved state and robots
+func (m *MemoryStore) SaveSnapshot(s *State, robots []Robot, eventID int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.state = *s
+	m.robots = append([]Robot{}, robots...)
+	m.eventID = eventID
+	return nil
+}
+
+// AppendEvent journals an event
+func (m *MemoryStore) AppendEvent(ev *Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.events = append(m.events, *ev)
+	return nil
+}
+
+// Events returns up to limit events after since, oldest first
+func (m *MemoryStore) Events(since, limit int) ([]Event, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	events := m.events[m.after(since):]
+	if limit > 0 && len(events) > limit {
+		events = events[:limit]
+	}
+	return append([]Event{}, events...), nil
+}
+
+// DropEvents removes the events up to and including through
+func (m *MemoryStore) DropEvents(through int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.events = append([]Event{}, m.events[m.after(through):]...)
+	return nil
+}
+
+// after is the index of the first event after id. The caller must hold the lock.
+func (m *MemoryStore) after(id int) int {
+	return sort.Search(len(m.events), func(i int) bool { return m.events[i].ID > id })
+}
+
+// SaveRound records a finished round
+func (m *MemoryStore) SaveRound(r *Round) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.rounds = append(m.rounds, *r)
+	return nil
+}
+
+// Rounds returns every finished round, oldest first
+func (m *MemoryStore) Rounds() ([]Round, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Round{}, m.rounds...), nil
+}
+
+// Account returns the account for key, or nil if there isn't one
+func (m *MemoryStore) Account(key string) (*Account, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	a, exists := m.accounts[key]
+	if !exists {
+		return nil, nil
+	}
+	return &a, nil
+}
+
+// SaveAccount adds or replaces an account
+func (m *MemoryStore) SaveAccount(a *Account) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.accounts[a.Key] = *a
+	return nil
+}
+
+// Accounts returns every account, by key
+func (m *MemoryStore) Accounts() ([]Account, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	accounts := []Account{}
+	for _, a := range m.accounts {
+		accounts = append(accounts, a)
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].Key < accounts[j].Key })
+	return accounts, nil
+}
+
+// Close does nothing, everything is lost with the process anyway
+func (m *MemoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"github.com/asdine/storm"
+	"github.com/asdine/storm/q"
+)
+
+// StormStore keeps the game in a BoltDB file
+type StormStore struct {
+	db *storm.DB
+}
+
+// OpenStormStore opens, or creates, the db at path
+func OpenStormStore(path string) (*StormStore, error) {
+	db, err := storm.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &StormStore{db: db}, nil
+}
+
+// LoadSnapshot returns the last saved state and robots, and the ID of the last event they include
+func (st *StormStore) LoadSnapshot() (*State, []Robot, int, error) {
+	var states []State
+	if err := st.db.All(&states); err != nil && err != storm.ErrNotFound {
+		return nil, nil, 0, err
+	}
+	var state State
+	if len(states) == 1 {
+		state = states[0]
+	}
+
+	var robots []Robot
+	if err := st.db.All(&robots); err != nil && err != storm.ErrNotFound {
+		return nil, nil, 0, err
+	}
+
+	var eventID int
+	if err := st.db.Get("snapshot", "event_id", &eventID); err != nil && err != storm.ErrNotFound {
+		return nil, nil, 0, err
+	}
+
+	return &state, robots, eventID, nil
+}
+
+// SaveSnapshot replaces the saved state and robots in one transaction
+func (st *StormStore) SaveSnapshot(s *State, robots []Robot, eventID int) error {
+	tx, err := st.db.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var saved []Robot
+	if err := tx.All(&saved); err != nil && err != storm.ErrNotFound {
+		return err
+	}
+	keep := map[string]bool{}
+	for _, r := range robots {
+		keep[r.ID] = true
+	}
+	for i := range saved {
+		if !keep[saved[i].ID] {
+			if err := tx.DeleteStruct(&saved[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i := range robots {
+		if err := tx.Save(&robots[i]); err != nil {
+			return err
+		}
+	}
+
+	state := *s
+	state.ID = 1 // hardcode id so there can only be one state
+	if err := tx.Save(&state); err != nil {
+		return err
+	}
+	if err := tx.Set("snapshot", "event_id", eventID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// AppendEvent journals an event
+func (st *StormStore) AppendEvent(ev *Event) error {
+	return st.db.Save(ev)
+}
+
+// Events returns up to limit events after since, oldest first
+func (st *StormStore) Events(since, limit int) ([]Event, error) {
+	query := st.db.Select(q.Gt("ID", since)).OrderBy("ID")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	events := []Event{}
+	if err := query.Find(&events); err != nil && err != storm.ErrNotFound {
+		return nil, err
+	}
+	return events, nil
+}
+
+// DropEvents removes the events up to and including through
+func (st *StormStore) DropEvents(through int) error {
+	if err := st.db.Select(q.Lte("ID", through)).Delete(new(Event)); err != nil && err != storm.ErrNotFound {
+		return err
+	}
+	return nil
+}
+
+// SaveRound records a finished round
+func (st *StormStore) SaveRound(r *Round) error {
+	return st.db.Save(r)
+}
+
+// Rounds returns every finished round, oldest first
+func (st *StormStore) Rounds() ([]Round, error) {
+	rounds := []Round{}
+	if err := st.db.All(&rounds); err != nil && err != storm.ErrNotFound {
+		return nil, err
+	}
+	return rounds, nil
+}
+
+// Account returns the account for key, or nil if there isn't one
+func (st *StormStore) Account(key string) (*Account, error) {
+	var a Account
+	if err := st.db.One("Key", key, &a); err != nil {
+		if err == storm.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &a, nil
+}
+
+// SaveAccount adds or replaces an account
+func (st *StormStore) SaveAccount(a *Account) error {
+	return st.db.Save(a)
+}
+
+// Accounts returns every account, by key
+func (st *StormStore) Accounts() ([]Account, error) {
+	accounts := []Account{}
+	if err := st.db.All(&accounts); err != nil && err != storm.ErrNotFound {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// Close the db
+func (st *StormStore) Close() error {
+	return st.db.Close()
+}
@@ -0,0 +1,268 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// pushInterval is how often a connection is sent the events it hasn't seen
+const pushInterval = 100 * time.Millisecond
+
+const tcpHelp = `commands, one per line:
+  JOIN XX      add a robot with initials XX and play it
+  RESUME ID    play a robot you already have
+  MOVE         move forward
+  TURN L|R     turn left or right
+  ATTACK       attack whatever is in front
+  LOOK         your robot and what it can see
+  STATE        the board
+  LEAVE        take your robot off the grid
+  QUIT         hang up, your robot stays
+answers are OK or OK followed by JSON, or ERR and why. Once playing, every event is sent as EVENT and JSON.`
+
+// session is one TCP connection and the robot it plays
+type session struct {
+	g    *Game
+	conn net.Conn
+	mu   sync.Mutex // guards writes, answers and pushes share the connection
+	id   string     // robot being played, empty until JOIN or RESUME
+	push chan int   // tells the pusher which event to start after, sent on the first JOIN or RESUME
+}
+
+// command is a TCP command handler, like an HTTP one it returns what to send back
+type command func(s *session, args []string) (interface{}, error)
+
+var commands = map[string]command{
+	"JOIN":   tcpJoin,
+	"RESUME": tcpResume,
+	"MOVE":   playing(func(s *session, args []string) error { return s.g.Move(s.id) }),
+	"TURN":   playing(tcpTurn),
+	"ATTACK": playing(func(s *session, args []string) error { return s.g.Attack(s.id) }),
+	"LOOK": func(s *session, args []string) (interface{}, error) {
+		if s.id == "" {
+			return nil, errNotPlaying
+		}
+		return s.g.Robot(s.id)
+	},
+	"STATE": func(s *session, args []string) (interface{}, error) {
+		state, err := s.g.State()
+		if err != nil {
+			return nil, err
+		}
+		return state.Public(), nil
+	},
+	"LEAVE": func(s *session, args []string) (interface{}, error) {
+		if s.id == "" {
+			return nil, errNotPlaying
+		}
+		if err := s.g.DeleteRobot(s.id); err != nil {
+			return nil, err
+		}
+		s.id = ""
+		return nil, nil
+	},
+}
+
+var errNotPlaying = fmt.Errorf("JOIN or RESUME a robot first")
+var errPlaying = fmt.Errorf("already playing a robot, LEAVE it first")
+
+// ServeTCP plays robots over a line based protocol on l, for bots that would rather keep a connection open than make
+// a request per action, or people with netcat. It returns when l is closed, or closes l when the game does.
+func (g *Game) ServeTCP(l net.Listener) error {
+	go func() {
+		<-g.done
+		l.Close()
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			select {
+			case <-g.done:
+				return nil
+			default:
+				return err
+			}
+		}
+		go g.serveConn(conn)
+	}
+}
+
+func (g *Game) serveConn(conn net.Conn) {
+	defer conn.Close()
+	log.Printf("tcp at=connect addr=%s\n", conn.RemoteAddr())
+
+	s := &session{g: g, conn: conn, push: make(chan int, 1)}
+	quit := make(chan struct{})
+	defer close(quit)
+	go s.pushEvents(s.push, quit)
+
+	s.write("HELLO robot-game, HELP for commands")
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		name := strings.ToUpper(fields[0])
+		if name == "QUIT" {
+			s.write("OK")
+			break
+		}
+		if name == "HELP" {
+			s.write(tcpHelp)
+			continue
+		}
+		cmd, exists := commands[name]
+		if !exists {
+			s.write("ERR unknown command " + fields[0] + ", HELP for commands")
+			continue
+		}
+
+		start := time.Now()
+		response, err := cmd(s, fields[1:])
+		log.Printf("tcp at=command command=%s duration=%s\n", name, time.Since(start))
+		if err != nil {
+			s.write("ERR " + err.Error())
+			continue
+		}
+		if response == nil {
+			s.write("OK")
+			continue
+		}
+		b, err := json.Marshal(response)
+		if err != nil {
+			s.write("ERR " + err.Error())
+			continue
+		}
+		s.write("OK " + string(b))
+	}
+	log.Printf("tcp at=disconnect addr=%s\n", conn.RemoteAddr())
+}
+
+// write sends one line, or several for HELP
+func (s *session) write(line string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	fmt.Fprintln(s.conn, line)
+}
+
+// pushEvents sends every event after the session starts playing, until quit
+func (s *session) pushEvents(start <-chan int, quit <-chan struct{}) {
+	const limit = 1000
+
+	var since int
+	select {
+	case since = <-start:
+	case <-quit:
+		return
+	case <-s.g.done:
+		return
+	}
+
+	ticker := time.NewTicker(pushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-quit:
+			return
+		case <-s.g.done:
+			return
+		}
+
+		for {
+			events, next, err := s.g.Events(EventFilter{}, since, limit)
+			if err != nil {
+				log.Println(err)
+				break
+			}
+			for _, ev := range events {
+				b, _ := json.Marshal(ev)
+				s.write("EVENT " + string(b))
+			}
+			since = next
+			if len(events) < limit {
+				break
+			}
+		}
+	}
+}
+
+// play makes the session's robot id, pushing events from now on if it isn't already
+func (s *session) play(id string) {
+	if s.push != nil {
+		w := s.g.world
+		w.mu.RLock()
+		s.push <- w.eventID
+		w.mu.RUnlock()
+		s.push = nil
+	}
+	s.id = id
+}
+
+// playing wraps an action that needs a robot, answering with the robot afterwards like the HTTP API does
+func playing(action func(s *session, args []string) error) command {
+	return func(s *session, args []string) (interface{}, error) {
+		if s.id == "" {
+			return nil, errNotPlaying
+		}
+		if err := action(s, args); err != nil {
+			return nil, err
+		}
+		return s.g.Robot(s.id)
+	}
+}
+
+func tcpJoin(s *session, args []string) (interface{}, error) {
+	if s.id != "" {
+		// Another robot would be left on the grid with nobody to play it
+		return nil, errPlaying
+	}
+	if len(args) != 1 {
+		return nil, fmt.Errorf("JOIN needs your initials")
+	}
+	robot, err := s.g.NewRobot(args[0])
+	if err != nil {
+		return nil, err
+	}
+	s.play(robot.ID)
+	return robot, nil
+}
+
+func tcpResume(s *session, args []string) (interface{}, error) {
+	if s.id != "" {
+		// Same as JOIN, the robot being played would be left with nobody to play it
+		return nil, errPlaying
+	}
+	if len(args) != 1 {
+		return nil, fmt.Errorf("RESUME needs the robot's ID")
+	}
+	robot, err := s.g.ResumeRobot(args[0])
+	if err != nil {
+		return nil, err
+	}
+	s.play(robot.ID)
+	return robot, nil
+}
+
+func tcpTurn(s *session, args []string) error {
+	if len(args) == 1 {
+		switch strings.ToUpper(args[0]) {
+		case "L", "LEFT":
+			return s.g.Turn(s.id, true)
+		case "R", "RIGHT":
+			return s.g.Turn(s.id, false)
+		}
+	}
+	return fmt.Errorf("TURN L or TURN R")
+}
@@ -0,0 +1,250 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// redrawEvery is how often the terminal game redraws the arena
+const redrawEvery = 250 * time.Millisecond
+
+// Keys the terminal game reads, already decoded from escape sequences
+const (
+	keyUp = iota + 1
+	keyLeft
+	keyRight
+	keyAttack
+	keyQuit
+)
+
+// terminal is the game played in a raw terminal, like an SSH session's
+type terminal struct {
+	g      *Game
+	in     *bufio.Reader
+	out    io.Writer
+	robot  *Robot
+	status string // how the last action went
+}
+
+// PlayTerminal runs the game full screen on rw, which should be a terminal in raw mode like an SSH pty. key is who
+// the player is: the first time they pick their initials and join, every time after they pick up the same robot.
+// It returns when they quit or rw fails.
+func (g *Game) PlayTerminal(rw io.ReadWriter, key string) error {
+	t := &terminal{g: g, in: bufio.NewReader(rw), out: rw}
+	if err := t.login(key); err != nil {
+		return err
+	}
+
+	keys := make(chan int)
+	quit := make(chan struct{})
+	defer close(quit)
+	go func() {
+		defer close(keys)
+		for {
+			k, err := t.readKey()
+			if err != nil {
+				return
+			}
+			if k == 0 {
+				continue
+			}
+			select {
+			case keys <- k:
+			case <-quit:
+				return
+			}
+		}
+	}()
+
+	// One action at a time, so it never has to wait to be heard
+	done := make(chan string, 1)
+	pending := ""
+	ticker := time.NewTicker(redrawEvery)
+	defer ticker.Stop()
+
+	for {
+		if err := t.draw(pending); err != nil {
+			return err
+		}
+
+		select {
+		case k, open := <-keys:
+			if !open || k == keyQuit {
+				t.printf("\x1b[2J\x1b[Hbye %s, your robot is kept for next time\r\n", t.robot.Name)
+				return nil
+			}
+			if pending != "" {
+				continue
+			}
+			pending = t.act(k, done)
+
+		case t.status = <-done:
+			pending = ""
+
+		case <-ticker.C:
+		case <-g.done:
+			return nil
+		}
+	}
+}
+
+// login picks up the robot on key's account, or has the player join with their initials and opens one
+func (t *terminal) login(key string) error {
+	a, err := t.g.Account(key)
+	if err != nil {
+		return err
+	}
+	if a != nil {
+		if r, err := t.g.ResumeRobot(a.RobotID); err == nil {
+			t.robot = r
+			return nil
+		}
+		// Deleted since, join again under the same account
+	}
+
+	t.printf("\x1b[2J\x1b[Hwelcome to robot-game\r\n\r\n")
+	for {
+		t.printf("your initials: ")
+		name, err := t.readLine()
+		if err != nil {
+			return err
+		}
+		r, err := t.g.NewRobot(name)
+		if err != nil {
+			t.printf("%v\r\n", err)
+			continue
+		}
+
+		t.robot = r
+		return t.g.SaveAccount(&Account{Key: key, Name: r.Name, RobotID: r.ID, CreatedAt: t.g.clock.Now()})
+	}
+}
+
+// act starts the action for k in the background, returning its name, or nothing if k isn't an action
+func (t *terminal) act(k int, done chan<- string) string {
+	id := t.robot.ID
+	var name string
+	var action func() error
+	switch k {
+	case keyUp:
+		name, action = "move", func() error { return t.g.Move(id) }
+	case keyLeft:
+		name, action = "turn left", func() error { return t.g.Turn(id, true) }
+	case keyRight:
+		name, action = "turn right", func() error { return t.g.Turn(id, false) }
+	case keyAttack:
+		name, action = "attack", func() error { return t.g.Attack(id) }
+	default:
+		return ""
+	}
+
+	go func() {
+		if err := action(); err != nil {
+			done <- name + ": " + err.Error()
+			return
+		}
+		done <- name + ": ok"
+	}()
+	return name
+}
+
+// draw clears the screen and shows the arena with the player's status on top
+func (t *terminal) draw(pending string) error {
+	if r, err := t.g.ResumeRobot(t.robot.ID); err == nil {
+		t.robot = r
+	}
+	board, err := t.g.RenderASCII()
+	if err != nil {
+		return err
+	}
+
+	r := t.robot
+	status := fmt.Sprintf("you are %s  score %d  ", r.Name, r.Score)
+	switch {
+	case r.Dead:
+		status += "dead until the round ends"
+	case pending != "":
+		status += pending + "..."
+	default:
+		status += t.status
+	}
+
+	var b strings.Builder
+	b.WriteString("\x1b[H\x1b[2J")
+	fmt.Fprintf(&b, "\x1b[7m%s\x1b[0m\n\n", status)
+	b.WriteString(board)
+	b.WriteString("\n↑ move  ←/→ turn  space attack  q quit\n")
+	_, err = io.WriteString(t.out, strings.ReplaceAll(b.String(), "\n", "\r\n"))
+	return err
+}
+
+func (t *terminal) printf(format string, args ...interface{}) {
+	fmt.Fprintf(t.out, format, args...)
+}
+
+// readLine reads what's typed up to enter, echoing it since a raw terminal won't
+func (t *terminal) readLine() (string, error) {
+	var line []byte
+	for {
+		c, err := t.in.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case c == '\r' || c == '\n':
+			t.printf("\r\n")
+			return string(line), nil
+		case c == 3 || c == 4:
+			// ctrl-c, ctrl-d
+			return "", io.EOF
+		case c == 127 || c == 8:
+			if len(line) > 0 {
+				line = line[:len(line)-1]
+				t.printf("\b \b")
+			}
+		case c >= ' ' && c < 127:
+			line = append(line, c)
+			t.printf("%c", c)
+		}
+	}
+}
+
+// readKey reads one key press, 0 for ones the game doesn't use
+func (t *terminal) readKey() (int, error) {
+	c, err := t.in.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	switch c {
+	case 'w', 'k':
+		return keyUp, nil
+	case 'a', 'h':
+		return keyLeft, nil
+	case 'd', 'l':
+		return keyRight, nil
+	case ' ':
+		return keyAttack, nil
+	case 'q', 3, 4:
+		return keyQuit, nil
+	case 0x1b:
+		// Arrows are ESC [ A to D
+		if c, err = t.in.ReadByte(); err != nil || c != '[' {
+			return 0, err
+		}
+		if c, err = t.in.ReadByte(); err != nil {
+			return 0, err
+		}
+		switch c {
+		case 'A':
+			return keyUp, nil
+		case 'D':
+			return keyLeft, nil
+		case 'C':
+			return keyRight, nil
+		}
+	}
+	return 0, nil
+}
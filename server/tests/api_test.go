@@ -14,6 +14,8 @@ func TestAPI(t *testing.T) {
 				"grid": 16,
 				"robots": [],
 				"round": 0,
+				"tick": 1,
+				"spawn_points": null,
 				"delay": 30000000,
 				"robot_limit": 1
 				}`, 200)
@@ -21,12 +23,12 @@ func TestAPI(t *testing.T) {
 		id := assertResponse(t, POST(t, "/robots", `{"name": "JP"}`),
 			`{
 				"dead":false, 
-				"x":1, 
-				"y":15, 
+				"x":6, 
+				"y":0, 
 				"score":0, 
 				"name":"JP", 
 				"color":"#e6194b", 
-				"direction":3, 
+				"direction":1, 
 				"vision":4,
 				"robots_in_range": []
 			}`, 200)
@@ -36,16 +38,18 @@ func TestAPI(t *testing.T) {
 				"grid": 16, 
 				"robots": [{
 					"dead":false, 
-					"x":1, 
-					"y":15, 
+					"x":6, 
+					"y":0, 
 					"score":0, 
 					"name":"JP", 
 					"color":"#e6194b", 
-					"direction":3, 
+					"direction":1, 
 					"vision":4,
 					"robots_in_range": null
 				}], 
 				"round": 0,
+				"tick": 2,
+				"spawn_points": null,
 				"delay": 30000000,
 				"robot_limit": 1
 			}`, 200)
@@ -53,12 +57,12 @@ func TestAPI(t *testing.T) {
 		assertResponse(t, GET(t, "/robots/"+id),
 			`{
 				"dead":false, 
-				"x":1, 
-				"y":15, 
+				"x":6, 
+				"y":0, 
 				"score":0, 
 				"name":"JP", 
 				"color":"#e6194b", 
-				"direction":3, 
+				"direction":1, 
 				"vision":4,
 				"robots_in_range": []
 			}`, 200)
@@ -66,12 +70,12 @@ func TestAPI(t *testing.T) {
 		assertResponse(t, POST(t, "/robots/"+id+"/move", ``),
 			`{
 				"dead":false, 
-				"x":0, 
-				"y":15, 
+				"x":7, 
+				"y":0, 
 				"score":0, 
 				"name":"JP", 
 				"color":"#e6194b", 
-				"direction":3, 
+				"direction":1, 
 				"vision":4,
 				"robots_in_range": []
 			}`, 200)
@@ -79,12 +83,12 @@ func TestAPI(t *testing.T) {
 		assertResponse(t, POST(t, "/robots/"+id+"/turn", `{"direction": false}`),
 			`{
 				"dead":false, 
-				"x":0, 
-				"y":15, 
+				"x":7, 
+				"y":0, 
 				"score":0, 
 				"name":"JP", 
 				"color":"#e6194b", 
-				"direction":0, 
+				"direction":2, 
 				"vision":4,
 				"robots_in_range": []
 			}`, 200)
@@ -92,12 +96,12 @@ func TestAPI(t *testing.T) {
 		assertResponse(t, POST(t, "/robots/"+id+"/move", ``),
 			`{
 				"dead":false, 
-				"x":0, 
-				"y":14, 
+				"x":7, 
+				"y":1, 
 				"score":0, 
 				"name":"JP", 
 				"color":"#e6194b", 
-				"direction":0, 
+				"direction":2, 
 				"vision":4,
 				"robots_in_range": []
 			}`, 200)
@@ -107,5 +111,92 @@ func TestAPI(t *testing.T) {
 				"at":"error", 
 				"msg": "swwwing and a missss"
 			}`, 200)
+
+		assertResponse(t, GET(t, "/events?robot=JP&since=2&limit=3"),
+			`{
+				"events": [
+					{"action":"move", "round":0, "tick":3, "time":"2019-03-01T12:00:00.03Z", "actor":"JP", "result":"ok"},
+					{"action":"turn", "round":0, "tick":4, "time":"2019-03-01T12:00:00.06Z", "actor":"JP", "result":"ok"},
+					{"action":"move", "round":0, "tick":5, "time":"2019-03-01T12:00:00.09Z", "actor":"JP", "result":"ok"}
+				],
+				"next": 5
+			}`, 200)
+
+		// Missing changed nothing, but it's history all the same
+		assertResponse(t, GET(t, "/events?type=attack"),
+			`{
+				"events": [
+					{"action":"attack", "round":0, "tick":6, "time":"2019-03-01T12:00:00.12Z", "actor":"JP", "result":"swwwing and a missss"}
+				],
+				"next": 6
+			}`, 200)
+	})
+
+	t.Run("admin export and import", func(t *testing.T) {
+		assertResponse(t, GET(t, "/admin/export"),
+			`{
+				"at":"error",
+				"msg": "not an admin"
+			}`, 401)
+
+		assertResponse(t, GET(t, "/admin/export").WithHeader("Authorization", "Bearer "+TestAdminToken),
+			`{
+				"version": 1,
+				"settings": {
+					"grid": 16,
+					"delay": 30000000,
+					"robot_limit": 1,
+					"spawn_protection": 3
+				},
+				"round": 0,
+				"seed": 1,
+				"map": {"spawn_points": null},
+				"robots": [{
+					"dead":false,
+					"x":7,
+					"y":1,
+					"score":0,
+					"name":"JP",
+					"color":"#e6194b",
+					"direction":2,
+					"vision":4,
+					"robots_in_range": null
+				}],
+				"accounts": []
+			}`, 200)
+
+		assertResponse(t, POST(t, "/admin/import", `{
+				"version": 1,
+				"settings": {"grid": 16},
+				"round": 5,
+				"seed": 2,
+				"map": {"spawn_points": [{"x": 0, "y": 0}]},
+				"robots": [{"id": "imported", "name": "ZZ", "x": 3, "y": 3, "direction": 1, "color": "#e6194b", "vision": 4, "score": 50}]
+			}`).WithHeader("Authorization", "Bearer "+TestAdminToken),
+			`{
+				"grid": 16,
+				"robots": [{
+					"dead":false,
+					"x":3,
+					"y":3,
+					"score":50,
+					"name":"ZZ",
+					"color":"#e6194b",
+					"direction":1,
+					"vision":4,
+					"robots_in_range": null
+				}],
+				"round": 5,
+				"tick": 7,
+				"spawn_points": [{"x": 0, "y": 0}],
+				"delay": 30000000,
+				"robot_limit": 1
+			}`, 200)
+
+		assertResponse(t, POST(t, "/admin/import", `{"version": 1, "settings": {"grid": 8}}`).WithHeader("Authorization", "Bearer "+TestAdminToken),
+			`{
+				"at":"error",
+				"msg": "export is for a 8 grid but this server plays on 16"
+			}`, 200)
 	})
 }
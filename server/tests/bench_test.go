@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/fanatic/robot-game/server"
+)
+
+// benchGame joins n robots to a fresh game whose clock never sleeps, so only the cost of the action itself is timed
+func benchGame(b *testing.B, n int) (*server.Game, []string) {
+	os.Remove("bench.db")
+	store, err := server.OpenStormStore("bench.db")
+	if err != nil {
+		b.Fatal(err)
+	}
+	g, err := server.NewGame(server.WithStore(store), server.WithSeed(1), server.WithClock(server.NewFakeClock(time.Now())))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	ids := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		// Players go by two letters, AA to ZZ
+		name := string([]byte{'A' + byte(i/26), 'A' + byte(i%26)})
+		r, err := g.NewRobot(name)
+		if err != nil {
+			b.Fatal(err)
+		}
+		ids = append(ids, r.ID)
+	}
+	return g, ids
+}
+
+func benchTeardown(g *server.Game) {
+	g.Close()
+	os.Remove("bench.db")
+}
+
+func BenchmarkActions(b *testing.B) {
+	for _, n := range []int{10, 100, 200} {
+		b.Run(fmt.Sprintf("robots=%d", n), func(b *testing.B) {
+			g, ids := benchGame(b, n)
+			defer benchTeardown(g)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				id := ids[i%len(ids)]
+				// Walls and neighbours make plenty of these fail, which is as much a part of the load as a success
+				if i%2 == 0 {
+					g.Move(id)
+				} else {
+					g.Turn(id, i%4 == 1)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkRobot(b *testing.B) {
+	for _, n := range []int{10, 100, 200} {
+		b.Run(fmt.Sprintf("robots=%d", n), func(b *testing.B) {
+			g, ids := benchGame(b, n)
+			defer benchTeardown(g)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := g.Robot(ids[i%len(ids)]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkState(b *testing.B) {
+	for _, n := range []int{10, 100, 200} {
+		b.Run(fmt.Sprintf("robots=%d", n), func(b *testing.B) {
+			g, _ := benchGame(b, n)
+			defer benchTeardown(g)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := g.State(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
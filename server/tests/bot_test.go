@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fanatic/robot-game/server"
+	"github.com/fanatic/robot-game/server/bot"
+	"github.com/fanatic/robot-game/server/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBrains(t *testing.T) {
+	// In the top right corner facing north, with a robot to the west
+	v := bot.View{
+		Robot: server.Robot{X: 15, Y: 0, Direction: server.North, InRange: []server.ShortRobot{{Name: "FP", X: 14, Y: 0}}},
+		Grid:  16,
+	}
+
+	require.Equal(t, bot.TurnRight, bot.WallHugger{}.Decide(v))
+	require.Equal(t, bot.TurnLeft, bot.NewHunter().Decide(v))
+
+	v.Robot.Direction = server.West
+	require.Equal(t, bot.Attack, bot.WallHugger{}.Decide(v))
+	require.Equal(t, bot.Attack, bot.NewHunter().Decide(v))
+	require.Equal(t, bot.Attack, bot.NewRandomWalker().Decide(v))
+
+	// Nothing about, the random walker never walks off the grid
+	v.Robot.InRange = nil
+	v.Robot.Direction = server.North
+	for i := 0; i < 100; i++ {
+		require.NotEqual(t, bot.Move, bot.NewRandomWalker().Decide(v))
+	}
+}
+
+func TestRunner(t *testing.T) {
+	g, _ := newGame(t)
+	defer g.Close()
+	h, err := server.New(g)
+	require.NoError(t, err)
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+
+	c, err := client.New(ts.URL)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	runner := bot.NewRunner(c, "HB", bot.NewHunter(), bot.WithLogger(bot.Discard))
+	require.NoError(t, runner.Run(ctx))
+
+	// It played at the server's pace, then left when the context was done
+	state, err := g.State()
+	require.NoError(t, err)
+	events, _, err := g.Events(server.EventFilter{Robot: "HB"}, 0, 100)
+	require.NoError(t, err)
+	require.Greater(t, len(events), 3)
+	require.LessOrEqual(t, len(events), 2+int(200*time.Millisecond/state.CurrentDelay)+1)
+	require.Equal(t, server.ActionJoin, events[0].Action)
+	require.Equal(t, server.ActionLeave, events[len(events)-1].Action)
+
+	// Names the game won't take stop the runner
+	err = bot.NewRunner(c, "TOO LONG", bot.WallHugger{}, bot.WithLogger(bot.Discard)).Run(context.Background())
+	require.EqualError(t, err, "name must be exactly 2 characters")
+}
@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fanatic/robot-game/server"
+	"github.com/fanatic/robot-game/server/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient(t *testing.T) {
+	g, _ := newGame(t)
+	defer g.Close()
+	h, err := server.New(g)
+	require.NoError(t, err)
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+
+	c, err := client.New(ts.URL+"/", client.WithPollInterval(10*time.Millisecond))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	r, err := c.Join(ctx, "JP")
+	require.NoError(t, err)
+	require.Equal(t, "JP", r.Name)
+	_, err = c.Join(ctx, "JP")
+	require.True(t, errors.Is(err, client.ErrLimit))
+
+	r, err = c.Move(ctx, r.ID)
+	require.NoError(t, err)
+	require.Equal(t, 7, r.X)
+	r, err = c.Turn(ctx, r.ID, false)
+	require.NoError(t, err)
+	require.Equal(t, server.South, r.Direction)
+	_, err = c.Attack(ctx, r.ID)
+	require.True(t, errors.Is(err, client.ErrMissed))
+
+	s, err := c.State(ctx)
+	require.NoError(t, err)
+	require.Len(t, s.Robots, 1)
+	require.Empty(t, s.Robots[0].ID)
+
+	events, next, err := c.Events(ctx, server.EventFilter{Action: server.ActionMove}, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.GreaterOrEqual(t, next, events[0].ID)
+
+	// Subscribe picks up from since and keeps going until the context is done
+	subCtx, cancel := context.WithCancel(ctx)
+	var seen []string
+	err = c.Subscribe(subCtx, server.EventFilter{Robot: "JP"}, 0, func(ev server.Event) {
+		seen = append(seen, ev.Action)
+		if len(seen) == 4 {
+			cancel()
+		}
+	})
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, []string{"join", "move", "turn", "attack"}, seen)
+
+	require.NoError(t, c.Leave(ctx, r.ID))
+	_, err = c.Robot(ctx, r.ID)
+	require.True(t, errors.Is(err, client.ErrNotFound))
+
+	_, _, err = c.Events(ctx, server.EventFilter{}, 0, 0)
+	require.EqualError(t, err, "limit must be between 1 and 1000")
+}
@@ -0,0 +1,68 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fanatic/robot-game/server"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportValidate(t *testing.T) {
+	g, _ := newGame(t)
+	defer g.Close()
+
+	_, err := g.NewRobot("JP")
+	require.NoError(t, err)
+	e, err := g.Export()
+	require.NoError(t, err)
+	require.NoError(t, e.Validate())
+
+	// Nothing a player couldn't have joined as
+	e.Robots[0].Name = "JPX"
+	require.EqualError(t, e.Validate(), "robot "+e.Robots[0].ID+"'s name must be exactly 2 characters")
+
+	e.Robots[0].Name = "JP"
+	twin := e.Robots[0]
+	twin.ID, twin.X = "twin", twin.X+1
+	if twin.X == 16 {
+		twin.X -= 2
+	}
+	e.Robots = append(e.Robots, twin)
+	require.EqualError(t, e.Validate(), "JP has more robots than the limit of 1")
+	require.EqualError(t, g.Import(e), "JP has more robots than the limit of 1")
+}
+
+func TestExportAccounts(t *testing.T) {
+	g, _ := newGame(t)
+	defer g.Close()
+
+	r, err := g.NewRobot("JP")
+	require.NoError(t, err)
+	require.NoError(t, g.SaveAccount(&server.Account{Key: "SHA256:jp", Name: "JP", RobotID: r.ID, CreatedAt: testStart}))
+	e, err := g.Export()
+	require.NoError(t, err)
+	require.Equal(t, []server.ExportAccount{{Key: "SHA256:jp", Name: "JP", RobotID: r.ID, CreatedAt: testStart}}, e.Accounts)
+
+	// Accounts come along with their robots
+	other, _ := newGame(t)
+	defer other.Close()
+	gone, err := other.NewRobot("FP")
+	require.NoError(t, err)
+	require.NoError(t, other.SaveAccount(&server.Account{Key: "SHA256:fp", Name: "FP", RobotID: gone.ID, CreatedAt: time.Now()}))
+	require.NoError(t, other.Import(e))
+
+	a, err := other.Account("SHA256:jp")
+	require.NoError(t, err)
+	require.Equal(t, r.ID, a.RobotID)
+	_, err = other.ResumeRobot(a.RobotID)
+	require.NoError(t, err)
+
+	// Those whose robots the import took away get a new one next time
+	a, err = other.Account("SHA256:fp")
+	require.NoError(t, err)
+	require.Empty(t, a.RobotID)
+
+	e.Accounts = append(e.Accounts, e.Accounts[0])
+	require.EqualError(t, e.Validate(), "account SHA256:jp appears twice")
+}
@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/fanatic/robot-game/server"
+	"github.com/fanatic/robot-game/server/pb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPC(t *testing.T) {
+	g, _ := newGame(t)
+	defer g.Close()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := server.NewGRPC(g)
+	go s.Serve(l)
+	defer s.Stop()
+
+	conn, err := grpc.NewClient(l.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	c := pb.NewRobotGameClient(conn)
+	ctx := context.Background()
+
+	_, err = c.Join(ctx, &pb.JoinRequest{Name: "TOO LONG"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = c.Move(ctx, &pb.RobotRequest{Id: "nobody"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	robot, err := c.Join(ctx, &pb.JoinRequest{Name: "gr"})
+	require.NoError(t, err)
+	require.Equal(t, "GR", robot.Name)
+	require.NotEmpty(t, robot.Id)
+
+	observe, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := c.Observe(observe, &pb.RobotRequest{Id: robot.Id})
+	require.NoError(t, err)
+	first, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, robot.X, first.Robot.X)
+
+	turned, err := c.Turn(ctx, &pb.TurnRequest{Id: robot.Id, Left: true})
+	require.NoError(t, err)
+	require.Equal(t, (robot.Direction+3)%4, turned.Direction)
+
+	// The turn is a new tick, so it's pushed
+	next, err := stream.Recv()
+	require.NoError(t, err)
+	require.Greater(t, next.Tick, first.Tick)
+	require.Equal(t, turned.Direction, next.Robot.Direction)
+
+	// Swinging at nothing is the game saying no, not the server failing
+	_, err = c.Attack(ctx, &pb.RobotRequest{Id: robot.Id})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	// It still took a tick, with the robot right where it was
+	missed, err := stream.Recv()
+	require.NoError(t, err)
+	require.Greater(t, missed.Tick, next.Tick)
+	require.Equal(t, next.Robot.X, missed.Robot.X)
+	require.Equal(t, next.Robot.Direction, missed.Robot.Direction)
+
+	state, err := c.GetState(ctx, &pb.StateRequest{})
+	require.NoError(t, err)
+	require.Len(t, state.Robots, 1)
+	require.Empty(t, state.Robots[0].Id)
+	require.Equal(t, int32(16), state.Grid)
+
+	_, err = c.Leave(ctx, &pb.RobotRequest{Id: robot.Id})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestGRPCErrors(t *testing.T) {
+	store := &failingStore{MemoryStore: server.NewMemoryStore()}
+	g, _ := newGame(t, server.WithStore(store), server.WithoutLoops())
+	defer g.Close()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := server.NewGRPC(g)
+	go s.Serve(l)
+	defer s.Stop()
+
+	conn, err := grpc.NewClient(l.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	c := pb.NewRobotGameClient(conn)
+	ctx := context.Background()
+
+	robot, err := c.Join(ctx, &pb.JoinRequest{Name: "GR"})
+	require.NoError(t, err)
+
+	// Once the store is failing nothing can be played, and that's the server's fault
+	store.setFail(true)
+	_, _, err = g.Events(server.EventFilter{}, 0, 10)
+	require.Error(t, err)
+	_, err = c.Move(ctx, &pb.RobotRequest{Id: robot.Id})
+	require.Equal(t, codes.Internal, status.Code(err))
+	store.setFail(false)
+}
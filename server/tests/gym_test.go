@@ -0,0 +1,179 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fanatic/robot-game/server/bot"
+	"github.com/fanatic/robot-game/server/gym"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGym(t *testing.T) {
+	_, err := gym.New(gym.Config{Opponents: []string{"boss"}})
+	require.EqualError(t, err, `unknown opponent "boss"`)
+
+	env, err := gym.New(gym.Config{Opponents: []string{"hunter", "random"}, Rewards: &gym.Rewards{Kill: 1, Death: -1, Win: 5, Survival: 0.01}, MaxSteps: 200, FullBoard: true})
+	require.NoError(t, err)
+	defer env.Close()
+
+	_, _, _, _, err = env.Step(bot.Move)
+	require.EqualError(t, err, "reset the environment first")
+
+	obs, err := env.Reset(3)
+	require.NoError(t, err)
+	require.Equal(t, [3]int{gym.Channels, 16, 16}, obs.Shape)
+	require.Equal(t, float32(1), obs.At(gym.ChannelSelf, obs.Robot.X, obs.Robot.Y))
+	require.Equal(t, float32(1), obs.At(gym.ChannelNorth+obs.Robot.Direction, obs.Robot.X, obs.Robot.Y))
+	enemies := 0
+	for _, v := range obs.Tensor[gym.ChannelEnemy*16*16 : (gym.ChannelEnemy+1)*16*16] {
+		enemies += int(v)
+	}
+	require.Equal(t, 2, enemies)
+
+	// play runs an episode with a brain, returning the total reward and the last step's info
+	play := func(seed int64) (float64, gym.Info) {
+		obs, err := env.Reset(seed)
+		require.NoError(t, err)
+		brain := bot.WallHugger{}
+		total := 0.0
+		for {
+			var reward float64
+			var done bool
+			var info gym.Info
+			obs, reward, done, info, err = env.Step(brain.Decide(bot.View{Robot: obs.Robot, Grid: obs.Shape[1]}))
+			require.NoError(t, err)
+			total += reward
+			if done {
+				require.LessOrEqual(t, info.Steps, 200)
+				_, _, _, _, err = env.Step(bot.Wait)
+				require.EqualError(t, err, "the episode is done, reset the environment")
+				return total, info
+			}
+		}
+	}
+	total, info := play(5)
+	require.NotZero(t, total)
+	require.NotZero(t, info.Steps)
+
+	// The same seed plays out the same
+	again, againInfo := play(5)
+	require.Equal(t, total, again)
+	require.Equal(t, info, againInfo)
+}
+
+func TestGymHTTP(t *testing.T) {
+	ts := httptest.NewServer(gym.NewHandler())
+	defer ts.Close()
+
+	post := func(path string, body interface{}, out interface{}) {
+		b, err := json.Marshal(body)
+		require.NoError(t, err)
+		res, err := http.Post(ts.URL+path, "application/json", bytes.NewReader(b))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.NoError(t, json.NewDecoder(res.Body).Decode(out))
+	}
+
+	var created struct {
+		ID       string   `json:"id"`
+		Channels int      `json:"channels"`
+		Actions  []string `json:"actions"`
+	}
+	post("/gym/envs", map[string]interface{}{"opponents": []string{"wall"}}, &created)
+	require.NotEmpty(t, created.ID)
+	require.Equal(t, gym.Channels, created.Channels)
+	require.Contains(t, created.Actions, "attack")
+
+	var reset struct {
+		Observation gym.Observation `json:"observation"`
+	}
+	post("/gym/envs/"+created.ID+"/reset", map[string]int64{"seed": 1}, &reset)
+	require.Len(t, reset.Observation.Tensor, gym.Channels*16*16)
+
+	var step struct {
+		Observation gym.Observation `json:"observation"`
+		Reward      float64         `json:"reward"`
+		Done        bool            `json:"done"`
+		Info        gym.Info        `json:"info"`
+	}
+	post("/gym/envs/"+created.ID+"/step", map[string]string{"action": "left"}, &step)
+	require.Equal(t, 1, step.Info.Steps)
+	require.Equal(t, (reset.Observation.Robot.Direction+3)%4, step.Observation.Robot.Direction)
+
+	var failed map[string]string
+	post("/gym/envs/"+created.ID+"/step", map[string]string{"action": "jump"}, &failed)
+	require.Equal(t, map[string]string{"at": "error", "msg": `unknown action "jump"`}, failed)
+
+	req, err := http.NewRequest(http.MethodDelete, ts.URL+"/gym/envs/"+created.ID, nil)
+	require.NoError(t, err)
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, 204, res.StatusCode)
+
+	post("/gym/envs/"+created.ID+"/step", map[string]string{"action": "move"}, &failed)
+	require.Equal(t, "not found", failed["msg"])
+}
+
+func TestGymIdle(t *testing.T) {
+	ts := httptest.NewServer(gym.NewHandler(gym.WithIdleTimeout(50 * time.Millisecond)))
+	defer ts.Close()
+
+	create := func() string {
+		res, err := http.Post(ts.URL+"/gym/envs", "application/json", strings.NewReader(`{"opponents": ["wall"]}`))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		var created struct {
+			ID string `json:"id"`
+		}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&created))
+		return created.ID
+	}
+	reset := func(id string) map[string]interface{} {
+		res, err := http.Post(ts.URL+"/gym/envs/"+id+"/reset", "application/json", strings.NewReader(`{"seed": 1}`))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		var answer map[string]interface{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&answer))
+		return answer
+	}
+
+	// Left alone, an environment is closed with the next request, whatever it's for
+	abandoned := create()
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, "not found", reset(abandoned)["msg"])
+	used := create()
+	require.Contains(t, reset(used), "observation")
+}
+
+func TestGymClose(t *testing.T) {
+	h := gym.NewHandler()
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+
+	create := func() map[string]interface{} {
+		res, err := http.Post(ts.URL+"/gym/envs", "application/json", strings.NewReader(`{"opponents": ["wall"]}`))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		var answer map[string]interface{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&answer))
+		return answer
+	}
+
+	// Shutting down closes what agents left open and turns new ones away
+	id := create()["id"].(string)
+	require.NoError(t, h.Close())
+	res, err := http.Post(ts.URL+"/gym/envs/"+id+"/reset", "application/json", strings.NewReader(`{"seed": 1}`))
+	require.NoError(t, err)
+	defer res.Body.Close()
+	var answer map[string]interface{}
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&answer))
+	require.Equal(t, "not found", answer["msg"])
+	require.Equal(t, "the gym is closed", create()["msg"])
+}
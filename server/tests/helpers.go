@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"os"
 	"reflect"
 	"testing"
 	"time"
@@ -19,20 +18,36 @@ import (
 
 var TestRouter http.Handler
 var TestGame *server.Game
+var TestAdminToken = "test-admin-token"
 
-func setup(t *testing.T) {
+// testStart is when every test's clock starts
+var testStart = time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// setup starts TestGame and TestRouter on a FakeClock of their own, so a test run with -count starts from scratch
+// every time
+func setup(t *testing.T) (*server.Game, *server.FakeClock) {
 	var err error
 
-	TestGame, err = server.NewGame("unit-test-api.db")
+	clock := server.NewFakeClock(testStart)
+	TestGame, err = server.NewGame(server.WithStore(server.NewMemoryStore()), server.WithSeed(1), server.WithClock(clock), server.WithAdminToken(TestAdminToken))
 	require.NoError(t, err)
 
 	TestRouter, err = server.New(TestGame)
 	require.NoError(t, err)
+	return TestGame, clock
 }
 
 func teardown() {
 	TestGame.Close()
-	os.Remove("unit-test-api.db")
+}
+
+// newGame starts a game for a test, seeded so spawns are the same every run and on a FakeClock of its own so
+// actions don't sleep. opts are applied after those.
+func newGame(t *testing.T, opts ...server.Option) (*server.Game, *server.FakeClock) {
+	clock := server.NewFakeClock(testStart)
+	g, err := server.NewGame(append([]server.Option{server.WithSeed(1), server.WithClock(clock)}, opts...)...)
+	require.NoError(t, err)
+	return g, clock
 }
 
 func newAPI(t *testing.T) *httpexpect.Expect {
@@ -77,7 +92,7 @@ func sanitize(a interface{}, keepIDFields bool) {
 			default:
 				if k == "id" && !keepIDFields {
 					delete(b, k)
-				} else if k == "created_at" || k == "resource_id" || k == "updated_at" {
+				} else if k == "created_at" || k == "protected_until" || k == "exported_at" || k == "resource_id" || k == "updated_at" {
 					delete(b, k)
 				}
 			}
@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fanatic/robot-game/server"
+	"github.com/stretchr/testify/require"
+)
+
+// failingStore is a MemoryStore whose journal can be made to fail
+type failingStore struct {
+	*server.MemoryStore
+
+	mu   sync.Mutex
+	fail bool
+}
+
+func (s *failingStore) AppendEvent(ev *server.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fail {
+		return errors.New("disk full")
+	}
+	return s.MemoryStore.AppendEvent(ev)
+}
+
+func (s *failingStore) setFail(fail bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fail = fail
+}
+
+func TestJournal(t *testing.T) {
+	store := &failingStore{MemoryStore: server.NewMemoryStore()}
+	g, _ := newGame(t, server.WithStore(store))
+
+	r, err := g.NewRobot("JP")
+	require.NoError(t, err)
+
+	// The world moves on while the store is failing, but only until the journal is found to be behind
+	store.setFail(true)
+	require.NoError(t, g.Move(r.ID))
+	_, _, err = g.Events(server.EventFilter{}, 0, 10)
+	require.EqualError(t, err, "disk full")
+	require.EqualError(t, g.Move(r.ID), "the journal can't be written: disk full")
+
+	// Then catches up, in order, once the store is back
+	store.setFail(false)
+	events, _, err := g.Events(server.EventFilter{}, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	for i, action := range []string{server.ActionSeed, server.ActionJoin, server.ActionMove} {
+		require.Equal(t, i+1, events[i].ID)
+		require.Equal(t, action, events[i].Action)
+	}
+	require.NoError(t, g.Move(r.ID))
+
+	require.NoError(t, g.Close())
+	require.NoError(t, g.Close())
+}
+
+func TestJournalHistory(t *testing.T) {
+	store := server.NewMemoryStore()
+	g, clock := newGame(t, server.WithStore(store), server.WithHistory(1), server.WithSpawnPoints(server.Location{X: 5, Y: 5}, server.Location{X: 5, Y: 6}))
+
+	a, err := g.NewRobot("JP")
+	require.NoError(t, err)
+	b, err := g.NewRobot("JW")
+	require.NoError(t, err)
+	for round := 0; round < 3; round++ {
+		kill(t, g, clock, a.ID, b.ID)
+	}
+	require.NoError(t, g.Close())
+
+	// Only the last finished round can still be replayed
+	rounds, err := store.Rounds()
+	require.NoError(t, err)
+	require.Len(t, rounds, 3)
+	events, err := store.Events(0, 0)
+	require.NoError(t, err)
+	require.Equal(t, rounds[1].EventID, events[0].ID)
+
+	g, err = server.NewGame(server.WithStore(store), server.WithClock(clock), server.WithHistory(1))
+	require.NoError(t, err)
+	defer g.Close()
+
+	_, err = g.Replay(1)
+	require.Error(t, err)
+	replay, err := g.Replay(2)
+	require.NoError(t, err)
+	require.NoError(t, replay.Verify())
+}
+
+func TestWithoutLoops(t *testing.T) {
+	store := server.NewMemoryStore()
+	g, _ := newGame(t, server.WithStore(store), server.WithoutLoops(), server.WithPopulation(2, server.NPCIdle))
+
+	r, err := g.NewRobot("JP")
+	require.NoError(t, err)
+	require.NoError(t, g.Move(r.ID))
+
+	// Nothing writes the journal behind the game's back, or plays the NPCs it would want
+	events, err := store.Events(0, 0)
+	require.NoError(t, err)
+	require.Empty(t, events)
+	s, err := g.State()
+	require.NoError(t, err)
+	require.Len(t, s.Robots, 1)
+
+	// Closing catches up
+	require.NoError(t, g.Close())
+	events, err = store.Events(0, 0)
+	require.NoError(t, err)
+	require.Len(t, events, 4)
+}
+
+// kill has the robot with id attacker turn to face, and kill, the one next to it with id victim
+func kill(t *testing.T, g *server.Game, clock *server.FakeClock, attacker, victim string) {
+	a, err := g.Robot(attacker)
+	require.NoError(t, err)
+	v, err := g.Robot(victim)
+	require.NoError(t, err)
+
+	facing := map[server.Location]int{{X: 0, Y: -1}: server.North, {X: 1, Y: 0}: server.East, {X: 0, Y: 1}: server.South, {X: -1, Y: 0}: server.West}
+	direction, adjacent := facing[server.Location{X: v.X - a.X, Y: v.Y - a.Y}]
+	require.True(t, adjacent)
+	for a.Direction != direction {
+		require.NoError(t, g.Turn(attacker, false))
+		a, err = g.Robot(attacker)
+		require.NoError(t, err)
+	}
+
+	clock.Advance(time.Second)
+	require.NoError(t, g.Attack(attacker))
+}
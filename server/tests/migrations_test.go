@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/asdine/storm"
+	"github.com/fanatic/robot-game/server"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrations(t *testing.T) {
+	os.Remove("unit-test-migrations.db")
+	defer os.Remove("unit-test-migrations.db")
+
+	// A db as the server left it before migrations existed: a state carrying its robots, and the robots themselves
+	db, err := storm.Open("unit-test-migrations.db")
+	require.NoError(t, err)
+	robot := server.Robot{ID: "old-robot", CreatedAt: time.Now(), Name: "JP", X: 3, Y: 4, Color: "#e6194b", Vision: 4, Score: 110}
+	require.NoError(t, db.Save(&robot))
+	require.NoError(t, db.Save(&server.State{ID: 1, Round: 7, Robots: []server.Robot{robot}}))
+	require.NoError(t, db.Close())
+
+	// Migrating records the version and takes the robots out of the state
+	store, err := server.OpenStormStore("unit-test-migrations.db")
+	require.NoError(t, err)
+	require.NoError(t, store.Migrate())
+	require.NoError(t, store.Close())
+
+	db, err = storm.Open("unit-test-migrations.db")
+	require.NoError(t, err)
+	var version int
+	require.NoError(t, db.Get("meta", "schema_version", &version))
+	require.Equal(t, 1, version)
+	var states []server.State
+	require.NoError(t, db.All(&states))
+	require.Len(t, states, 1)
+	require.Equal(t, 7, states[0].Round)
+	require.Empty(t, states[0].Robots)
+	require.NoError(t, db.Close())
+
+	store, err = server.OpenStormStore("unit-test-migrations.db")
+	require.NoError(t, err)
+	g, err := server.NewGame(server.WithStore(store), server.WithSeed(1))
+	require.NoError(t, err)
+
+	s, err := g.State()
+	require.NoError(t, err)
+	require.Equal(t, 7, s.Round)
+	require.Len(t, s.Robots, 1)
+	require.Equal(t, 110, s.Robots[0].Score)
+	require.NoError(t, g.Close())
+
+	// Opening again finds nothing left to migrate
+	store, err = server.OpenStormStore("unit-test-migrations.db")
+	require.NoError(t, err)
+	g, err = server.NewGame(server.WithStore(store), server.WithSeed(1))
+	require.NoError(t, err)
+	s, err = g.State()
+	require.NoError(t, err)
+	require.Equal(t, 7, s.Round)
+	require.NoError(t, g.Close())
+}
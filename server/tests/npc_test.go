@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fanatic/robot-game/server"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNPCs(t *testing.T) {
+	g, clock := newGame(t)
+	defer g.Close()
+
+	// turn lets the NPCs play once, waiting for the loop to be asleep before and after
+	turn := func() {
+		clock.BlockUntil(1)
+		clock.Advance(time.Second)
+		clock.BlockUntil(1)
+	}
+
+	_, err := g.AddNPC("boss")
+	require.EqualError(t, err, "difficulty must be idle, wanderer or hunter")
+
+	npc, err := g.AddNPC(server.NPCHunter)
+	require.NoError(t, err)
+	require.Equal(t, "H1", npc.Name)
+	require.Equal(t, server.NPCHunter, npc.NPC)
+
+	player, err := g.NewRobot("JP")
+	require.NoError(t, err)
+	require.EqualError(t, g.RemoveNPC(player.ID), "that robot is a player")
+
+	// Players can't take names like the NPCs'
+	_, err = g.NewRobot("h2")
+	require.EqualError(t, err, "name must be letters - names with digits are kept for npcs")
+
+	// The hunter plays by itself, through the same actions as everyone
+	for i := 0; i < 3; i++ {
+		turn()
+	}
+	events, _, err := g.Events(server.EventFilter{Robot: "H1"}, 0, 100)
+	require.NoError(t, err)
+	require.Len(t, events, 4)
+
+	require.NoError(t, g.RemoveNPC(npc.ID))
+
+	// Idle NPCs fill the grid up to the minimum population, and make room for players
+	require.NoError(t, g.SetPopulation(server.Population{Minimum: 4, Difficulty: server.NPCIdle}))
+	robots := func() int {
+		s, err := g.State()
+		require.NoError(t, err)
+		return len(s.Robots)
+	}
+	for i := 0; i < 3; i++ {
+		turn()
+	}
+	require.Equal(t, 4, robots())
+
+	_, err = g.NewRobot("FP")
+	require.NoError(t, err)
+	require.Equal(t, 5, robots())
+	turn()
+	require.Equal(t, 4, robots())
+
+	require.EqualError(t, g.SetPopulation(server.Population{Minimum: 4, Difficulty: "boss"}), "difficulty must be idle, wanderer or hunter")
+}
+
+func TestNPCPopulation(t *testing.T) {
+	store := server.NewMemoryStore()
+	g, clock := newGame(t, server.WithStore(store), server.WithPopulation(2, server.NPCWanderer))
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+	clock.BlockUntil(1)
+
+	// The population is the admins' business
+	s, err := g.State()
+	require.NoError(t, err)
+	require.Nil(t, s.Public().Population)
+
+	// It's kept along with the game, and exported with it
+	require.NoError(t, g.SetPopulation(server.Population{Minimum: 3, Difficulty: server.NPCHunter}))
+	e, err := g.Export()
+	require.NoError(t, err)
+	require.Equal(t, &server.Population{Minimum: 3, Difficulty: server.NPCHunter}, e.Settings.Population)
+	require.NoError(t, g.Close())
+
+	g, err = server.NewGame(server.WithStore(store), server.WithClock(clock))
+	require.NoError(t, err)
+	require.Equal(t, server.Population{Minimum: 3, Difficulty: server.NPCHunter}, g.Population())
+	require.NoError(t, g.Close())
+
+	imported, _ := newGame(t)
+	defer imported.Close()
+	require.NoError(t, imported.Import(e))
+	require.Equal(t, server.Population{Minimum: 3, Difficulty: server.NPCHunter}, imported.Population())
+}
@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fanatic/robot-game/server"
+	"github.com/fanatic/robot-game/server/bot"
+	"github.com/fanatic/robot-game/server/sim"
+	"github.com/stretchr/testify/require"
+)
+
+// script writes a shell script bot and returns its path
+func script(t *testing.T, body string) string {
+	path := filepath.Join(t.TempDir(), "bot.sh")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0755))
+	return path
+}
+
+func TestProcess(t *testing.T) {
+	v := bot.View{Robot: server.Robot{Name: "SH", X: 3, Y: 3}, Grid: 16}
+
+	// Answers one line per view
+	p, err := bot.NewProcess(time.Second, script(t, `while read view; do echo "$view" | grep -q '"grid":16' && echo attack || echo left; done`))
+	require.NoError(t, err)
+	require.Equal(t, bot.Attack, p.Decide(v))
+	require.Equal(t, bot.Attack, p.Decide(v))
+	require.NoError(t, p.Err())
+	require.NoError(t, p.Close())
+
+	// Too slow waits the turn
+	p, err = bot.NewProcess(50*time.Millisecond, script(t, "while read view; do sleep 1; echo move; done"))
+	require.NoError(t, err)
+	require.Equal(t, bot.Wait, p.Decide(v))
+	require.Contains(t, p.Err().Error(), "took longer than 50ms to answer")
+	require.NoError(t, p.Close())
+
+	// A late answer is never taken for the next turn's
+	p, err = bot.NewProcess(200*time.Millisecond, script(t, "read first; read second; echo move; echo left; while read view; do echo left; done"))
+	require.NoError(t, err)
+	require.Equal(t, bot.Wait, p.Decide(v))
+	require.Equal(t, bot.TurnLeft, p.Decide(v))
+	require.Equal(t, bot.TurnLeft, p.Decide(v))
+	require.NoError(t, p.Close())
+
+	// Nonsense waits the turn too
+	p, err = bot.NewProcess(time.Second, script(t, "while read view; do echo jump; done"))
+	require.NoError(t, err)
+	require.Equal(t, bot.Wait, p.Decide(v))
+	require.EqualError(t, p.Err(), `unknown action "jump"`)
+	require.NoError(t, p.Close())
+
+	// Crashes are restarted, a few times in a row
+	p, err = bot.NewProcess(time.Second, script(t, "read view; echo move; exit 3"))
+	require.NoError(t, err)
+	require.Equal(t, bot.Move, p.Decide(v))
+	require.Equal(t, bot.Wait, p.Decide(v))
+	require.Contains(t, p.Err().Error(), "exited: exit status 3")
+	require.Equal(t, bot.Move, p.Decide(v))
+	require.NoError(t, p.Close())
+
+	p, err = bot.NewProcess(time.Second, script(t, "exit 3"))
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		require.Equal(t, bot.Wait, p.Decide(v))
+	}
+	require.Contains(t, p.Err().Error(), "exited")
+	require.NoError(t, p.Close())
+
+	_, err = bot.NewProcess(time.Second, "no-such-bot")
+	require.Error(t, err)
+}
+
+func TestProcessSim(t *testing.T) {
+	walker := script(t, `while read view; do echo move; done`)
+	s := sim.New(sim.Config{Matches: 3, Seed: 1, MaxActions: 50})
+	p, err := bot.NewProcess(time.Second, walker)
+	require.NoError(t, err)
+	require.NoError(t, s.Register("SH", func(int64) bot.Brain { return p }))
+	require.NoError(t, s.Register("WH", func(int64) bot.Brain { return bot.WallHugger{} }))
+
+	results, err := s.Run()
+	require.NoError(t, err)
+	for _, b := range results.Bots {
+		require.NotZero(t, b.Actions)
+	}
+	// Stopped after every match, but that's no reason to give up on it
+	require.NoError(t, p.Err())
+}
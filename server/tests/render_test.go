@@ -0,0 +1,53 @@
+package tests
+
+import (
+	"bytes"
+	"image/gif"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	g, _ := newGame(t)
+	defer g.Close()
+
+	r, err := g.NewRobot("JP")
+	require.NoError(t, err)
+	g.Move(r.ID)
+
+	var buf bytes.Buffer
+	require.NoError(t, g.RenderPNG(&buf))
+	img, err := png.Decode(&buf)
+	require.NoError(t, err)
+	require.Equal(t, 16*40+1, img.Bounds().Dx())
+
+	replay, err := g.Replay(0)
+	require.NoError(t, err)
+	buf.Reset()
+	require.NoError(t, replay.RenderGIF(&buf))
+	anim, err := gif.DecodeAll(&buf)
+	require.NoError(t, err)
+	require.Len(t, anim.Image, len(replay.Actions)+1)
+}
+
+func TestRenderASCII(t *testing.T) {
+	g, _ := newGame(t)
+	defer g.Close()
+
+	r, err := g.NewRobot("JP")
+	require.NoError(t, err)
+
+	text, err := g.RenderASCII()
+	require.NoError(t, err)
+	require.Contains(t, text, "round 0  tick 2\n")
+	require.Contains(t, text, "\n  0   .   .   .   .   .   . JP>   .   .   .   .   .   .   .   .   .\n")
+	require.Contains(t, text, "\nJP   x=6  y=0  facing east  score=0\n")
+
+	// A robot only sees the cells next to it
+	text, err = g.RenderRobotASCII(r.ID)
+	require.NoError(t, err)
+	require.Contains(t, text, "\n  0                       . JP>   .\n  1                           .\n  2\n")
+	require.Contains(t, text, "nothing in range\n")
+}
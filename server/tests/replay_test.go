@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fanatic/robot-game/server"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplay(t *testing.T) {
+	g, clock := newGame(t, server.WithSpawnPoints(server.Location{X: 5, Y: 5}, server.Location{X: 5, Y: 6}))
+	defer g.Close()
+
+	a, err := g.NewRobot("JP")
+	require.NoError(t, err)
+	b, err := g.NewRobot("FP")
+	require.NoError(t, err)
+	require.NoError(t, g.Turn(b.ID, true))
+	require.NoError(t, g.Turn(a.ID, true))
+	refused := g.Attack(b.ID)
+	require.Error(t, refused)
+	kill(t, g, clock, a.ID, b.ID)
+
+	r, err := g.Replay(0)
+	require.NoError(t, err)
+	var results []string
+	for _, action := range r.Actions {
+		if action.Action == server.ActionAttack {
+			results = append(results, action.Result)
+		}
+	}
+	require.Equal(t, []string{refused.Error(), "ok"}, results)
+	last := r.Actions[len(r.Actions)-1]
+	require.Equal(t, server.ActionAttack, last.Action)
+	require.Equal(t, "JP", last.Robot)
+
+	// The round ends with FP dead where it fell, not respawned for the next one
+	final := map[string]server.Robot{}
+	for _, robot := range r.Final {
+		final[robot.Name] = robot
+	}
+	require.True(t, final["FP"].Dead)
+	require.Equal(t, 5, final["JP"].X)
+
+	// A replay file plays out the same after the trip through JSON
+	buf, err := json.Marshal(r)
+	require.NoError(t, err)
+	var loaded server.Replay
+	require.NoError(t, json.Unmarshal(buf, &loaded))
+	require.NoError(t, loaded.Verify())
+
+	frames, err := loaded.Frames()
+	require.NoError(t, err)
+	require.Len(t, frames, len(loaded.Actions)+1)
+	require.Nil(t, frames[0].Action)
+	end := frames[len(frames)-1]
+	require.Equal(t, loaded.Final, end.Robots)
+	require.Equal(t, "FP", end.Killed)
+	require.Equal(t, "JP", end.Winner)
+
+	loaded.Final[0].X++
+	require.Error(t, loaded.Verify())
+
+	// Files are checked before anything is drawn from them
+	loaded.Final[0].Direction = 7
+	_, err = loaded.Frames()
+	require.EqualError(t, err, "as the round ended: robot "+loaded.Final[0].ID+" has unknown direction 7")
+
+	// The next round starts where the journal says this one ended
+	next, err := g.Replay(1)
+	require.NoError(t, err)
+	require.Empty(t, next.Actions)
+	require.Len(t, next.Robots, 2)
+	require.NotEqual(t, r.Seed, next.Seed)
+	require.NoError(t, next.Verify())
+
+	_, err = g.Replay(2)
+	require.EqualError(t, err, "round 2 hasn't started")
+}
+
+func TestReplayAPI(t *testing.T) {
+	g, clock := newGame(t, server.WithSpawnPoints(server.Location{X: 5, Y: 5}, server.Location{X: 5, Y: 6}))
+	defer g.Close()
+	h, err := server.New(g)
+	require.NoError(t, err)
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+
+	a, err := g.NewRobot("JP")
+	require.NoError(t, err)
+	b, err := g.NewRobot("FP")
+	require.NoError(t, err)
+	kill(t, g, clock, a.ID, b.ID)
+
+	get := func(path string) map[string]interface{} {
+		res, err := http.Get(ts.URL + path)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		return body
+	}
+
+	// Finished rounds can be watched again, seed and all
+	finished := get("/rounds/0/replay")
+	require.Equal(t, float64(0), finished["round"])
+	seeded := finished["actions"].([]interface{})[0].(map[string]interface{})
+	require.Equal(t, server.ActionSeed, seeded["action"])
+	require.Equal(t, float64(1), seeded["seed"])
+
+	// The one being played would give its seed away
+	require.Equal(t, map[string]interface{}{"at": "error", "msg": "round 1 isn't over yet"}, get("/rounds/1/replay"))
+	require.Equal(t, map[string]interface{}{"at": "error", "msg": "round 1 isn't over yet"}, get("/rounds/1/replay.gif"))
+}
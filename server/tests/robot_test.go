@@ -0,0 +1,152 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fanatic/robot-game/server"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResumeRobot(t *testing.T) {
+	g, _ := newGame(t)
+	defer g.Close()
+
+	r, err := g.NewRobot("JP")
+	require.NoError(t, err)
+	require.NoError(t, g.Move(r.ID))
+
+	// Joining again is over the limit, resuming isn't
+	_, err = g.NewRobot("JP")
+	require.EqualError(t, err, "no more robots - you're at the limit")
+
+	resumed, err := g.ResumeRobot(r.ID)
+	require.NoError(t, err)
+	require.Equal(t, r.ID, resumed.ID)
+	require.Equal(t, r.X+1, resumed.X)
+
+	_, err = g.ResumeRobot("not-a-robot")
+	require.EqualError(t, err, "not found")
+
+	// Initials are the same whichever way they're typed, and whichever front end they come through
+	r, err = g.NewRobot("jw")
+	require.NoError(t, err)
+	require.Equal(t, "JW", r.Name)
+	_, err = g.NewRobot("JWW")
+	require.EqualError(t, err, "name must be exactly 2 characters")
+}
+
+func TestSpawning(t *testing.T) {
+	points := []server.Location{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 15, Y: 15}}
+	g, _ := newGame(t, server.WithSpawnPoints(points...))
+	defer g.Close()
+	require.Equal(t, points, g.SpawnPoints())
+
+	// The second robot gets the free spawn point furthest from the first
+	first, err := g.NewRobot("JP")
+	require.NoError(t, err)
+	second, err := g.NewRobot("JW")
+	require.NoError(t, err)
+
+	distance := func(p server.Location) int {
+		return abs(p.X-first.X) + abs(p.Y-first.Y)
+	}
+	furthest := -1
+	for _, p := range points {
+		if p != (server.Location{X: first.X, Y: first.Y}) && distance(p) > furthest {
+			furthest = distance(p)
+		}
+	}
+	require.Contains(t, points, server.Location{X: second.X, Y: second.Y})
+	require.Equal(t, furthest, distance(server.Location{X: second.X, Y: second.Y}))
+
+	// Once every spawn point is taken there's nowhere left to join
+	_, err = g.NewRobot("AB")
+	require.NoError(t, err)
+	_, err = g.NewRobot("CD")
+	require.EqualError(t, err, "no free location on the grid")
+
+	require.EqualError(t, g.SetSpawnPoints([]server.Location{{X: 16, Y: 0}}), "spawn point 16,0 is off the grid")
+	require.EqualError(t, g.SetSpawnPoints([]server.Location{{X: 1, Y: 1}, {X: 1, Y: 1}}), "spawn point 1,1 is given twice")
+}
+
+func TestSpawnProtection(t *testing.T) {
+	g, clock := newGame(t, server.WithSpawnPoints(server.Location{X: 5, Y: 5}))
+	defer g.Close()
+
+	attacker, err := g.NewRobot("JP")
+	require.NoError(t, err)
+	for attacker.Direction != server.South {
+		require.NoError(t, g.Turn(attacker.ID, false))
+		attacker, err = g.Robot(attacker.ID)
+		require.NoError(t, err)
+	}
+
+	// Right in front of the attacker, but only just spawned
+	require.NoError(t, g.SetSpawnPoints([]server.Location{{X: 5, Y: 6}}))
+	victim, err := g.NewRobot("JW")
+	require.NoError(t, err)
+	require.EqualError(t, g.Attack(attacker.ID), "that robot just spawned - give it a moment")
+
+	// Someone out of the way, so the kill doesn't end the round
+	require.NoError(t, g.SetSpawnPoints([]server.Location{{X: 10, Y: 10}}))
+	_, err = g.NewRobot("AB")
+	require.NoError(t, err)
+
+	clock.Advance(time.Second)
+	require.NoError(t, g.Attack(attacker.ID))
+	_, err = g.Robot(victim.ID)
+	require.EqualError(t, err, "this robot be dead")
+
+	// The killer is the one who acted
+	deaths, _, err := g.Events(server.EventFilter{Action: server.ActionDeath}, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, deaths, 1)
+	require.Equal(t, "JP", deaths[0].Actor)
+	require.Equal(t, "JW", deaths[0].Target)
+
+	// The refused attack is history too, it just changed nothing
+	attacks, _, err := g.Events(server.EventFilter{Action: server.ActionAttack}, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, attacks, 2)
+	require.Equal(t, "that robot just spawned - give it a moment", attacks[0].Result)
+	require.Empty(t, attacks[0].Robots)
+	require.Equal(t, "ok", attacks[1].Result)
+}
+
+func TestRespawnPastSpawnPoints(t *testing.T) {
+	g, clock := newGame(t, server.WithSpawnPoints(server.Location{X: 5, Y: 5}, server.Location{X: 5, Y: 6}))
+	defer g.Close()
+
+	a, err := g.NewRobot("JP")
+	require.NoError(t, err)
+	b, err := g.NewRobot("JW")
+	require.NoError(t, err)
+
+	// One spawn point for two robots, the next round still starts with both on the grid
+	require.NoError(t, g.SetSpawnPoints([]server.Location{{X: 0, Y: 0}}))
+	kill(t, g, clock, a.ID, b.ID)
+
+	s, err := g.State()
+	require.NoError(t, err)
+	require.Equal(t, 1, s.Round)
+	require.Len(t, s.Robots, 2)
+	spawned := map[server.Location]bool{}
+	for _, r := range s.Robots {
+		require.False(t, r.Dead)
+		spawned[server.Location{X: r.X, Y: r.Y}] = true
+	}
+	require.True(t, spawned[server.Location{X: 0, Y: 0}])
+	require.Len(t, spawned, 2)
+
+	// Joining still needs a free spawn point
+	_, err = g.NewRobot("AB")
+	require.EqualError(t, err, "no free location on the grid")
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/fanatic/robot-game/server/bot"
+	"github.com/fanatic/robot-game/server/sim"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSim(t *testing.T) {
+	run := func() *sim.Results {
+		s := sim.New(sim.Config{Matches: 10, Seed: 7})
+		require.NoError(t, s.Register("WH", func(int64) bot.Brain { return bot.WallHugger{} }))
+		require.NoError(t, s.Register("HU", func(seed int64) bot.Brain {
+			return &bot.Hunter{RandomWalker: bot.RandomWalker{Rand: rand.New(rand.NewSource(seed))}}
+		}))
+		require.EqualError(t, s.Register("HU", nil), "HU is already registered")
+		require.EqualError(t, s.Register("HUNTER", nil), "name must be exactly 2 characters")
+
+		results, err := s.Run()
+		require.NoError(t, err)
+		return results
+	}
+
+	results := run()
+	require.Equal(t, 10, results.Matches)
+	require.Len(t, results.Bots, 2)
+
+	wins, kills, deaths := results.Draws, 0, 0
+	for _, b := range results.Bots {
+		wins += b.Wins
+		kills += b.Kills
+		deaths += b.Deaths
+		require.NotZero(t, b.Actions)
+	}
+	// Every match has a winner or is drawn for everyone, and every kill is somebody's death
+	require.Equal(t, 10, wins)
+	for _, b := range results.Bots {
+		require.Equal(t, results.Draws, b.Draws)
+	}
+	require.Equal(t, kills, deaths)
+
+	// The same seed plays out the same
+	require.Equal(t, results, run())
+
+	var table bytes.Buffer
+	require.NoError(t, results.WriteTable(&table))
+	require.Contains(t, table.String(), "10 matches")
+
+	_, err := sim.New(sim.Config{}).Run()
+	require.EqualError(t, err, "a match needs at least 2 bots")
+}
@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"crypto/rand"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fanatic/robot-game/server"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestServeSSH(t *testing.T) {
+	g, _ := newGame(t)
+	addr, stop := serveSSH(t, g)
+	defer stop()
+
+	key := signer(t)
+	keys, waitFor, hangUp := dialSSH(t, addr, key)
+	waitFor("your initials: ")
+	_, err := keys.Write([]byte("jp\r"))
+	require.NoError(t, err)
+	waitFor("you are JP")
+	_, err = keys.Write([]byte("q"))
+	require.NoError(t, err)
+	waitFor("bye JP")
+	hangUp()
+
+	// The key is the account
+	a, err := g.Account(ssh.FingerprintSHA256(key.PublicKey()))
+	require.NoError(t, err)
+	require.Equal(t, "JP", a.Name)
+}
+
+func TestServeSSHReconnect(t *testing.T) {
+	os.Remove("unit-test-ssh.db")
+	defer os.Remove("unit-test-ssh.db")
+	key := signer(t)
+
+	store, err := server.OpenStormStore("unit-test-ssh.db")
+	require.NoError(t, err)
+	g, _ := newGame(t, server.WithStore(store))
+	addr, stop := serveSSH(t, g)
+	keys, waitFor, hangUp := dialSSH(t, addr, key)
+	waitFor("your initials: ")
+	_, err = keys.Write([]byte("jp\r"))
+	require.NoError(t, err)
+	waitFor("you are JP")
+	_, err = keys.Write([]byte("q"))
+	require.NoError(t, err)
+	waitFor("bye JP")
+	hangUp()
+	stop()
+
+	// The account outlives the server, so the same key gets the same robot back without being asked who it is
+	store, err = server.OpenStormStore("unit-test-ssh.db")
+	require.NoError(t, err)
+	g, _ = newGame(t, server.WithStore(store))
+	addr, stop = serveSSH(t, g)
+	defer stop()
+	a, err := g.Account(ssh.FingerprintSHA256(key.PublicKey()))
+	require.NoError(t, err)
+	require.NotEmpty(t, a.RobotID)
+
+	keys, waitFor, hangUp = dialSSH(t, addr, key)
+	waitFor("you are JP")
+	_, err = keys.Write([]byte("q"))
+	require.NoError(t, err)
+	waitFor("bye JP")
+	hangUp()
+}
+
+// serveSSH serves g on a port of its own, returning where to dial and a func that closes the game and checks it
+// stopped serving cleanly
+func serveSSH(t *testing.T, g *server.Game) (string, func()) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	served := make(chan error)
+	go func() { served <- g.ServeSSH(l, signer(t)) }()
+	return l.Addr().String(), func() {
+		require.NoError(t, g.Close())
+		require.NoError(t, <-served)
+	}
+}
+
+// dialSSH opens a terminal on addr as key. It returns where to type, a func that reads the screen until it shows s,
+// and a func that waits for the session to end and hangs up.
+func dialSSH(t *testing.T, addr string, key ssh.Signer) (io.Writer, func(s string), func()) {
+	conn, err := ssh.Dial("tcp", addr, &ssh.ClientConfig{
+		User:            "player",
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(key)},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+	})
+	require.NoError(t, err)
+
+	session, err := conn.NewSession()
+	require.NoError(t, err)
+	require.NoError(t, session.RequestPty("xterm", 40, 80, ssh.TerminalModes{}))
+	keys, err := session.StdinPipe()
+	require.NoError(t, err)
+	out, err := session.StdoutPipe()
+	require.NoError(t, err)
+	screen := bufio.NewReader(out)
+	require.NoError(t, session.Shell())
+
+	waitFor := func(s string) {
+		var seen strings.Builder
+		for !strings.Contains(seen.String(), s) {
+			b, err := screen.ReadByte()
+			require.NoError(t, err, seen.String())
+			seen.WriteByte(b)
+		}
+	}
+	return keys, waitFor, func() {
+		require.NoError(t, session.Wait())
+		session.Close()
+		conn.Close()
+	}
+}
+
+// signer makes a new key
+func signer(t *testing.T) ssh.Signer {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	s, err := ssh.NewSignerFromKey(private)
+	require.NoError(t, err)
+	return s
+}
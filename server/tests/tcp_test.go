@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/fanatic/robot-game/server"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTCP(t *testing.T) {
+	g, _ := newGame(t)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	served := make(chan error)
+	go func() { served <- g.ServeTCP(l) }()
+	defer func() {
+		require.NoError(t, g.Close())
+		require.NoError(t, <-served)
+	}()
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	lines := bufio.NewScanner(conn)
+
+	// send a command and return the answer, skipping pushed events
+	send := func(cmd string) string {
+		fmt.Fprintln(conn, cmd)
+		for lines.Scan() {
+			if !strings.HasPrefix(lines.Text(), "EVENT ") {
+				return lines.Text()
+			}
+		}
+		require.NoError(t, lines.Err())
+		return ""
+	}
+	require.True(t, lines.Scan())
+	require.Equal(t, "HELLO robot-game, HELP for commands", lines.Text())
+
+	require.Equal(t, "ERR JOIN or RESUME a robot first", send("move"))
+	require.Equal(t, "ERR unknown command FLY, HELP for commands", send("FLY"))
+	require.Equal(t, "ERR name must be exactly 2 characters", send("JOIN ABC"))
+
+	answer := send("JOIN tc")
+	require.True(t, strings.HasPrefix(answer, "OK {"), answer)
+	var robot server.Robot
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(answer, "OK ")), &robot))
+	require.Equal(t, "TC", robot.Name)
+	require.Equal(t, "ERR already playing a robot, LEAVE it first", send("JOIN tc"))
+	require.NotEmpty(t, robot.ID)
+	require.Equal(t, "ERR already playing a robot, LEAVE it first", send("RESUME "+robot.ID))
+
+	require.Equal(t, "ERR TURN L or TURN R", send("TURN"))
+	answer = send("TURN L")
+	require.True(t, strings.HasPrefix(answer, "OK {"), answer)
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(answer, "OK ")), &robot))
+
+	// Someone else's moves are pushed as they happen
+	other, err := g.NewRobot("OT")
+	require.NoError(t, err)
+	require.NoError(t, g.Turn(other.ID, false))
+	for lines.Scan() {
+		if strings.HasPrefix(lines.Text(), "EVENT ") && strings.Contains(lines.Text(), `"actor":"OT"`) {
+			var ev server.Event
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(lines.Text(), "EVENT ")), &ev))
+			require.Empty(t, ev.RobotID)
+			break
+		}
+	}
+
+	// The board without anything secret
+	answer = send("STATE")
+	require.True(t, strings.HasPrefix(answer, "OK {"))
+	var state server.State
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(answer, "OK ")), &state))
+	require.Len(t, state.Robots, 2)
+	require.Empty(t, state.Robots[0].ID)
+	require.Zero(t, state.Seed)
+	require.Equal(t, "OK", send("LEAVE"))
+	require.Equal(t, "ERR JOIN or RESUME a robot first", send("LOOK"))
+	require.Equal(t, "ERR JOIN needs your initials", send("JOIN"))
+	require.Equal(t, "OK", send("QUIT"))
+}
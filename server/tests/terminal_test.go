@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/fanatic/robot-game/server"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlayTerminal(t *testing.T) {
+	g, _ := newGame(t)
+	defer g.Close()
+
+	// play connects as key, returning the player's end and what the game sends
+	play := func(key string) (net.Conn, *bufio.Reader, chan error) {
+		player, game := net.Pipe()
+		played := make(chan error, 1)
+		go func() {
+			played <- g.PlayTerminal(game, key)
+			game.Close()
+		}()
+		return player, bufio.NewReader(player), played
+	}
+	// press types keys without waiting for the game to read them, it echoes as it goes
+	press := func(player net.Conn, keys string) {
+		go player.Write([]byte(keys))
+	}
+	// waitFor reads the screen until it shows s
+	waitFor := func(screen *bufio.Reader, s string) {
+		var seen strings.Builder
+		for !strings.Contains(seen.String(), s) {
+			b, err := screen.ReadByte()
+			require.NoError(t, err, seen.String())
+			seen.WriteByte(b)
+		}
+	}
+
+	player, screen, played := play("SHA256:first")
+	waitFor(screen, "your initials: ")
+	press(player, "abc\r")
+	waitFor(screen, "name must be exactly 2 characters")
+	press(player, "jp\r")
+	waitFor(screen, "you are JP")
+
+	// Arrow right turns
+	press(player, "\x1b[C")
+	waitFor(screen, "turn right: ok")
+	press(player, "q")
+	waitFor(screen, "bye JP")
+	player.Close()
+	require.NoError(t, <-played)
+
+	a, err := g.Account("SHA256:first")
+	require.NoError(t, err)
+	require.Equal(t, "JP", a.Name)
+	events, _, err := g.Events(server.EventFilter{Robot: "JP", Action: server.ActionTurn}, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	// The same key gets the same robot back without joining again
+	player, screen, played = play("SHA256:first")
+	waitFor(screen, "you are JP")
+	player.Close()
+	<-played
+	s, err := g.State()
+	require.NoError(t, err)
+	require.Len(t, s.Robots, 1)
+}
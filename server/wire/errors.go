@@ -0,0 +1,15 @@
+package wire
+
+import "errors"
+
+// The ways the game turns an action down. Clients match on these, so they only change together with the server.
+var (
+	ErrNotFound  = errors.New("not found")
+	ErrDead      = errors.New("this robot be dead")
+	ErrLimit     = errors.New("no more robots - you're at the limit")
+	ErrGridFull  = errors.New("no free location on the grid")
+	ErrOffGrid   = errors.New("off the grid")
+	ErrBlocked   = errors.New("something's in the way")
+	ErrMissed    = errors.New("swwwing and a missss")
+	ErrProtected = errors.New("that robot just spawned - give it a moment")
+)
@@ -0,0 +1,65 @@
+package wire
+
+import "time"
+
+// Event actions
+const (
+	ActionJoin       = "join"
+	ActionLeave      = "leave"
+	ActionMove       = "move"
+	ActionTurn       = "turn"
+	ActionAttack     = "attack"
+	ActionDeath      = "death"
+	ActionRound      = "round"
+	ActionSeed       = "seed"
+	ActionImport     = "import"
+	ActionMap        = "map"
+	ActionPopulation = "population"
+)
+
+// Event is something that happened in the game along with the records it changed. Events are journaled as they are
+// applied, so replaying the ones newer than the last snapshot brings the world back after a crash, and the journal
+// doubles as the game's history.
+type Event struct {
+	ID     int       `json:"id" storm:"id,increment"`
+	Action string    `json:"action"`
+	Round  int       `json:"round"`
+	Tick   int       `json:"tick"`
+	Time   time.Time `json:"time"`
+	Actor  string    `json:"actor,omitempty"`  // name of the robot that acted
+	Target string    `json:"target,omitempty"` // name of the robot acted upon
+	Result string    `json:"result"`           // "ok", or why the action failed
+
+	// Private, robot IDs are secrets
+	RobotID string   `json:"robot_id,omitempty"` // ID of the robot that acted
+	Left    bool     `json:"left,omitempty"`     // which way a turn went
+	Robots  []Robot  `json:"robots,omitempty"`   // robots as they are after the action
+	Deleted []string `json:"deleted,omitempty"`  // robots that left
+	State   *State   `json:"state,omitempty"`    // set when the round changes
+}
+
+// Public returns the event without the private fields
+func (ev Event) Public() Event {
+	ev.RobotID = ""
+	ev.Robots = nil
+	ev.Deleted = nil
+	ev.State = nil
+	return ev
+}
+
+// EventFilter narrows down Events. Empty fields match everything.
+type EventFilter struct {
+	Action string
+	Robot  string // name of the robot as actor or target
+}
+
+// Match reports whether ev is one the filter lets through
+func (f EventFilter) Match(ev *Event) bool {
+	if f.Action != "" && ev.Action != f.Action {
+		return false
+	}
+	if f.Robot != "" && ev.Actor != f.Robot && ev.Target != f.Robot {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,28 @@
+package wire
+
+import "time"
+
+// Robot is the player
+type Robot struct {
+	ID             string       `json:"id,omitempty"` // also the secret
+	CreatedAt      time.Time    `json:"created_at"`
+	Name           string       `json:"name"`
+	X              int          `json:"x"`
+	Y              int          `json:"y"`
+	Color          string       `json:"color"`
+	Direction      int          `json:"direction"`
+	Vision         int          `json:"vision"`
+	Score          int          `json:"score"`
+	Dead           bool         `json:"dead"`
+	ProtectedUntil time.Time    `json:"protected_until"` // can't be attacked until then
+	NPC            string       `json:"npc,omitempty"`   // difficulty of a robot the server plays, empty for players
+	InRange        []ShortRobot `json:"robots_in_range"`
+}
+
+// ShortRobot is used when sharing enemy robots
+type ShortRobot struct {
+	Name      string `json:"name"`
+	X         int    `json:"x"`
+	Y         int    `json:"y"`
+	Direction int    `json:"direction"`
+}
@@ -0,0 +1,40 @@
+package wire
+
+import "time"
+
+// State saves the current round to the db to allow for restarts
+type State struct {
+	// Saved values
+	ID          int         `json:"-"`
+	Round       int         `json:"round"`
+	Seed        int64       `json:"seed,omitempty"` // random seed the round is played with, secret until it's over
+	Tick        int         `json:"tick"`           // advances with every event
+	SpawnPoints []Location  `json:"spawn_points"`
+	Population  *Population `json:"population,omitempty"` // NPCs the server makes up the numbers with, if any
+
+	// Values not saved
+	Grid   int     `json:"grid"`
+	Robots []Robot `json:"robots"`
+
+	// Values returned to UI only
+	CurrentDelay      time.Duration `json:"delay"`
+	CurrentRobotLimit int           `json:"robot_limit"`
+}
+
+// Population is how many robots the server keeps on the grid, making up the numbers with NPCs
+type Population struct {
+	Minimum    int    `json:"minimum"`
+	Difficulty string `json:"difficulty"`
+}
+
+// Public returns the state without robot IDs, the population, which is for admins, or the seed, which would give
+// away where robots are going to spawn
+func (s State) Public() State {
+	s.Seed = 0
+	s.Population = nil
+	s.Robots = append([]Robot{}, s.Robots...)
+	for i := range s.Robots {
+		s.Robots[i].ID = ""
+	}
+	return s
+}
@@ -0,0 +1,17 @@
+// Package wire holds the types the game sends to its players: robots, the board and events. Clients and bots
+// import it instead of the server, so they don't link everything the server needs to run.
+package wire
+
+// Directional names for robot direction
+const (
+	North = iota
+	East
+	South
+	West
+)
+
+// Location is a coordinate on the grid
+type Location struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
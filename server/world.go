@@ -0,0 +1,192 @@
+package server
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+
+	"github.com/fanatic/robot-game/server/wire"
+)
+
+var errNotFound = wire.ErrNotFound
+var errDead = wire.ErrDead
+
+// World is the authoritative, in-memory copy of the game. Storm only holds periodic snapshots of it plus a journal
+// of the events applied since (see journal.go), which is enough to rebuild it after a crash.
+type World struct {
+	mu      sync.RWMutex
+	state   State
+	robots  []*Robot // in join order
+	byID    map[string]*Robot
+	cells   map[Location]*Robot // living robots by position
+	eventID int                 // last journaled event applied
+}
+
+func newWorld(s State, robots []Robot) *World {
+	s.Grid = gridSize
+	s.Robots = nil
+
+	w := &World{
+		state: s,
+		byID:  map[string]*Robot{},
+		cells: map[Location]*Robot{},
+	}
+
+	// Snapshots don't keep join order, creation time is the next best thing
+	sort.SliceStable(robots, func(i, j int) bool { return robots[i].CreatedAt.Before(robots[j].CreatedAt) })
+	for _, r := range robots {
+		w.put(r)
+	}
+	return w
+}
+
+// put adds or replaces a robot, keeping the spatial index in step
+func (w *World) put(r Robot) {
+	r.InRange = nil
+
+	if old, exists := w.byID[r.ID]; exists {
+		w.unindex(old)
+		*old = r
+		w.index(old)
+		return
+	}
+
+	robot := &r
+	w.robots = append(w.robots, robot)
+	w.byID[r.ID] = robot
+	w.index(robot)
+}
+
+// remove drops a robot from the world
+func (w *World) remove(id string) {
+	r, exists := w.byID[id]
+	if !exists {
+		return
+	}
+	w.unindex(r)
+	delete(w.byID, id)
+	for i, robot := range w.robots {
+		if robot == r {
+			w.robots = append(w.robots[:i], w.robots[i+1:]...)
+			break
+		}
+	}
+}
+
+func (w *World) index(r *Robot) {
+	if !r.Dead {
+		w.cells[Location{X: r.X, Y: r.Y}] = r
+	}
+}
+
+func (w *World) unindex(r *Robot) {
+	l := Location{X: r.X, Y: r.Y}
+	if w.cells[l] == r {
+		delete(w.cells, l)
+	}
+}
+
+// apply an already journaled event
+func (w *World) apply(ev *Event) {
+	if ev.State != nil {
+		s := *ev.State
+		s.Grid = gridSize
+		s.Robots = nil
+		w.state = s
+	}
+	if ev.Tick > w.state.Tick {
+		w.state.Tick = ev.Tick
+	}
+	for _, r := range ev.Robots {
+		w.put(r)
+	}
+	for _, id := range ev.Deleted {
+		w.remove(id)
+	}
+	if ev.ID > w.eventID {
+		w.eventID = ev.ID
+	}
+}
+
+// rng returns the random source for the next event. It's derived from the round's seed and the tick rather than
+// carried along, so any event can be reproduced without replaying everything before it, even across restarts.
+func (w *World) rng() *rand.Rand {
+	return rand.New(rand.NewSource(w.state.Seed + int64(w.state.Tick+1)))
+}
+
+// robot returns a copy of a robot, which is safe to modify and put back
+func (w *World) robot(id string) (Robot, error) {
+	r, exists := w.byID[id]
+	if !exists {
+		return Robot{}, errNotFound
+	}
+	return *r, nil
+}
+
+// living returns a copy of a robot that is still in the round
+func (w *World) living(id string) (Robot, error) {
+	r, err := w.robot(id)
+	if err != nil {
+		return r, err
+	}
+	if r.Dead {
+		return r, errDead
+	}
+	return r, nil
+}
+
+// robotAt returns the living robot on x, y, if any
+func (w *World) robotAt(x, y int) *Robot {
+	return w.cells[Location{X: x, Y: y}]
+}
+
+func (w *World) onGrid(l Location) bool {
+	return l.X >= 0 && l.X < w.state.Grid && l.Y >= 0 && l.Y < w.state.Grid
+}
+
+// copyRobots returns every robot in join order
+func (w *World) copyRobots() []Robot {
+	robots := make([]Robot, 0, len(w.robots))
+	for _, r := range w.robots {
+		robots = append(robots, *r)
+	}
+	return robots
+}
+
+// robotsInRange returns a list of robots within the vision of the current robot
+func (w *World) robotsInRange(r *Robot) []ShortRobot {
+	// TODO(jp): support more than vision=4
+	inRange := []ShortRobot{}
+	adjacent := adjacentGridLocations(r.X, r.Y)
+	for direction := North; direction <= West; direction++ {
+		l := adjacent[direction]
+		if robot := w.robotAt(l.X, l.Y); robot != nil {
+			inRange = append(inRange, ShortRobot{Name: robot.Name, X: robot.X, Y: robot.Y, Direction: robot.Direction})
+		}
+	}
+	return inRange
+}
+
+// named is true when a robot on the grid, dead or alive, is called name
+func (w *World) named(name string) bool {
+	for _, r := range w.robots {
+		if r.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// nearest returns the living robot closest to r, or nil if r is on its own
+func (w *World) nearest(r Robot) *Robot {
+	var closest *Robot
+	for _, other := range w.robots {
+		if other.ID == r.ID || other.Dead {
+			continue
+		}
+		if closest == nil || distance(Location{X: r.X, Y: r.Y}, Location{X: other.X, Y: other.Y}) < distance(Location{X: r.X, Y: r.Y}, Location{X: closest.X, Y: closest.Y}) {
+			closest = other
+		}
+	}
+	return closest
+}